@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/pagination"
 	"github.com/ZaguanLabs/groq-go/groq/types"
 )
 
@@ -82,3 +83,34 @@ func (b *Batches) List(ctx context.Context, req *types.ListBatchesRequest, opts
 	}
 	return &result, nil
 }
+
+// ListAutoPaging iterates over all batches, starting after req.After and
+// fetching req.Limit batches per page
+//
+//	for batch, err := range client.Batches.ListAutoPaging(ctx, nil).All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(batch.ID, batch.Status)
+//	}
+func (b *Batches) ListAutoPaging(ctx context.Context, req *types.ListBatchesRequest, opts ...option.RequestOption) *pagination.Iter[types.Batch] {
+	return pagination.NewIter(ctx, func(ctx context.Context, cursor string) (*pagination.Page[types.Batch], error) {
+		var pageReq types.ListBatchesRequest
+		if req != nil {
+			pageReq = *req
+		}
+		if cursor != "" {
+			pageReq.After = option.Ptr(option.Some(cursor))
+		}
+
+		list, err := b.List(ctx, &pageReq, opts...)
+		if err != nil {
+			return nil, err
+		}
+		page := &pagination.Page[types.Batch]{Data: list.Data, HasMore: list.HasMore, NextCursor: list.LastID}
+		if page.NextCursor == "" && len(list.Data) > 0 {
+			page.NextCursor = list.Data[len(list.Data)-1].ID
+		}
+		return page, nil
+	})
+}
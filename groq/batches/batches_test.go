@@ -416,3 +416,45 @@ func TestBatches_WithContext(t *testing.T) {
 		t.Fatal("expected context cancellation error")
 	}
 }
+
+func TestBatches_ListAutoPaging(t *testing.T) {
+	listPages := map[string]*types.BatchListResponse{
+		"":        {Data: []types.Batch{{ID: "batch-1"}, {ID: "batch-2"}}, LastID: "batch-2", HasMore: true},
+		"batch-2": {Data: []types.Batch{{ID: "batch-3"}, {ID: "batch-4"}}, HasMore: true}, // LastID missing
+		"batch-4": {Data: []types.Batch{{ID: "batch-5"}}},
+	}
+
+	var queries []map[string]string
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			var o option.RequestOptions
+			for _, opt := range opts {
+				opt(&o)
+			}
+			queries = append(queries, o.QueryParams)
+			*result.(*types.BatchListResponse) = *listPages[o.QueryParams["after"]]
+			return nil
+		},
+	}
+
+	b := New(mock)
+	var ids []string
+	for batch, err := range b.ListAutoPaging(context.Background(), &types.ListBatchesRequest{Limit: option.Ptr(option.Some(2))}).All() {
+		if err != nil {
+			t.Fatalf("ListAutoPaging error: %v", err)
+		}
+		ids = append(ids, batch.ID)
+	}
+
+	if strings.Join(ids, ",") != "batch-1,batch-2,batch-3,batch-4,batch-5" {
+		t.Errorf("ids = %v", ids)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("fetched %d pages, want 3", len(queries))
+	}
+	for i, after := range []string{"", "batch-2", "batch-4"} {
+		if queries[i]["after"] != after || queries[i]["limit"] != "2" {
+			t.Errorf("page %d query = %v", i, queries[i])
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package batches
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// Endpoints a batch can target
+const (
+	EndpointChatCompletions = "/v1/chat/completions"
+	EndpointEmbeddings      = "/v1/embeddings"
+	EndpointTranscriptions  = "/v1/audio/transcriptions"
+)
+
+// Limits of a batch input file
+const (
+	MaxInputLines = 50000
+	MaxInputBytes = 200 << 20
+)
+
+var (
+	// ErrInputTooLarge is returned when a line would exceed MaxInputLines or
+	// MaxInputBytes
+	ErrInputTooLarge = errors.New("batches: input exceeds the batch file limits")
+	// ErrDuplicateCustomID is returned when a custom ID is reused
+	ErrDuplicateCustomID = errors.New("batches: duplicate custom_id")
+)
+
+// InputLine is one request of a batch input file
+type InputLine struct {
+	CustomID string      `json:"custom_id"`
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Body     interface{} `json:"body"`
+}
+
+// FileUploader uploads files; it is implemented by files.Files
+type FileUploader interface {
+	Create(ctx context.Context, req *types.CreateFileRequest, opts ...option.RequestOption) (*types.FileObject, error)
+}
+
+// InputBuilder writes a batch input file line by line. Every request must
+// target the same endpoint and have a unique custom ID.
+//
+//	input, err := batches.NewInputFileBuilder()
+//	defer input.Close()
+//	for id, req := range requests {
+//		if err := input.AddChatCompletion(id, req); err != nil {
+//			return err
+//		}
+//	}
+//	file, err := input.Upload(ctx, client.Files)
+type InputBuilder struct {
+	w        io.Writer
+	file     *os.File // Set for builders created by NewInputFileBuilder
+	endpoint string
+	ids      map[string]bool
+	lines    int
+	size     int64
+}
+
+// NewInputBuilder returns a builder that streams JSONL to w
+func NewInputBuilder(w io.Writer) *InputBuilder {
+	return &InputBuilder{w: w, ids: make(map[string]bool)}
+}
+
+// NewInputFileBuilder returns a builder that writes to a temporary file,
+// which Upload sends and Close removes
+func NewInputFileBuilder() (*InputBuilder, error) {
+	f, err := os.CreateTemp("", "groq-batch-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	b := NewInputBuilder(f)
+	b.file = f
+	return b, nil
+}
+
+// AddChatCompletion adds a chat completion request. Streaming requests are
+// rejected.
+func (b *InputBuilder) AddChatCompletion(customID string, req *types.CreateChatCompletionRequest) error {
+	if req.Stream != nil && req.Stream.IsSet() && req.Stream.Value {
+		return fmt.Errorf("batches: %s: streaming is not supported in batches", customID)
+	}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("batches: %s: %w", customID, err)
+	}
+	return b.Add(customID, EndpointChatCompletions, req)
+}
+
+// AddEmbedding adds an embeddings request
+func (b *InputBuilder) AddEmbedding(customID string, req *types.CreateEmbeddingRequest) error {
+	if req.Model == "" {
+		return fmt.Errorf("batches: %s: %w", customID, &types.FieldError{Field: "model", Message: "is required"})
+	}
+	return b.Add(customID, EndpointEmbeddings, req)
+}
+
+// AddTranscription adds a transcription request. Batches cannot upload
+// audio, so req.URL must be set instead of req.File.
+func (b *InputBuilder) AddTranscription(customID string, req *types.CreateTranscriptionRequest) error {
+	if req.File != nil {
+		return fmt.Errorf("batches: %s: %w", customID, &types.FieldError{Field: "file", Message: "is not supported in batches; use url"})
+	}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("batches: %s: %w", customID, err)
+	}
+
+	// Send the form fields as a JSON body
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("batches: %s: %w", customID, err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return fmt.Errorf("batches: %s: %w", customID, err)
+	}
+	delete(body, "file")
+	if g, ok := body["timestamp_granularities[]"]; ok {
+		delete(body, "timestamp_granularities[]")
+		body["timestamp_granularities"] = g
+	}
+	return b.Add(customID, EndpointTranscriptions, body)
+}
+
+// Add writes a request with an arbitrary body for endpoint
+func (b *InputBuilder) Add(customID, endpoint string, body interface{}) error {
+	if customID == "" {
+		return errors.New("batches: custom_id is required")
+	}
+	if b.ids[customID] {
+		return fmt.Errorf("%w: %q", ErrDuplicateCustomID, customID)
+	}
+	if b.endpoint != "" && endpoint != b.endpoint {
+		return fmt.Errorf("batches: %s: endpoint %s differs from %s; a batch targets a single endpoint", customID, endpoint, b.endpoint)
+	}
+
+	line, err := json.Marshal(InputLine{CustomID: customID, Method: "POST", URL: endpoint, Body: body})
+	if err != nil {
+		return fmt.Errorf("batches: %s: %w", customID, err)
+	}
+	line = append(line, '\n')
+
+	if b.lines+1 > MaxInputLines {
+		return fmt.Errorf("%w: more than %d lines", ErrInputTooLarge, MaxInputLines)
+	}
+	if b.size+int64(len(line)) > MaxInputBytes {
+		return fmt.Errorf("%w: more than %d bytes", ErrInputTooLarge, MaxInputBytes)
+	}
+	if _, err := b.w.Write(line); err != nil {
+		return err
+	}
+
+	b.endpoint = endpoint
+	b.ids[customID] = true
+	b.lines++
+	b.size += int64(len(line))
+	return nil
+}
+
+// Len returns the number of requests written
+func (b *InputBuilder) Len() int {
+	return b.lines
+}
+
+// Size returns the number of bytes written
+func (b *InputBuilder) Size() int64 {
+	return b.size
+}
+
+// Endpoint returns the endpoint of the requests written, or "" if none
+func (b *InputBuilder) Endpoint() string {
+	return b.endpoint
+}
+
+// Upload uploads the temporary file of a builder created by
+// NewInputFileBuilder with purpose "batch"
+func (b *InputBuilder) Upload(ctx context.Context, uploader FileUploader, opts ...option.RequestOption) (*types.FileObject, error) {
+	if b.file == nil {
+		return nil, errors.New("batches: Upload requires a builder created by NewInputFileBuilder")
+	}
+	if b.lines == 0 {
+		return nil, errors.New("batches: input is empty")
+	}
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return uploader.Create(ctx, &types.CreateFileRequest{File: b.file, Purpose: types.FilePurposeBatch}, opts...)
+}
+
+// Close removes the temporary file of a builder created by
+// NewInputFileBuilder
+func (b *InputBuilder) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	err := b.file.Close()
+	if rmErr := os.Remove(b.file.Name()); err == nil {
+		err = rmErr
+	}
+	return err
+}
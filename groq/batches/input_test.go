@@ -0,0 +1,204 @@
+package batches
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func chatRequest(content string) *types.CreateChatCompletionRequest {
+	return &types.CreateChatCompletionRequest{
+		Model:    "llama-3.1-8b-instant",
+		Messages: []types.ChatCompletionMessageParam{{Role: types.RoleUser, Content: content}},
+	}
+}
+
+func TestInputBuilder_ChatCompletions(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewInputBuilder(&buf)
+
+	if err := b.AddChatCompletion("req-1", chatRequest("Hello")); err != nil {
+		t.Fatalf("AddChatCompletion error: %v", err)
+	}
+	if err := b.AddChatCompletion("req-2", chatRequest("World")); err != nil {
+		t.Fatalf("AddChatCompletion error: %v", err)
+	}
+
+	want := `{"custom_id":"req-1","method":"POST","url":"/v1/chat/completions","body":{"messages":[{"role":"user","content":"Hello"}],"model":"llama-3.1-8b-instant"}}` + "\n" +
+		`{"custom_id":"req-2","method":"POST","url":"/v1/chat/completions","body":{"messages":[{"role":"user","content":"World"}],"model":"llama-3.1-8b-instant"}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if b.Len() != 2 || b.Size() != int64(len(want)) || b.Endpoint() != EndpointChatCompletions {
+		t.Errorf("Len = %d, Size = %d, Endpoint = %q", b.Len(), b.Size(), b.Endpoint())
+	}
+}
+
+func TestInputBuilder_Errors(t *testing.T) {
+	streaming := chatRequest("Hi")
+	streaming.Stream = option.Ptr(option.Some(true))
+
+	tests := []struct {
+		name    string
+		add     func(b *InputBuilder) error
+		wantErr error
+		wantMsg string
+	}{
+		{"duplicate custom_id", func(b *InputBuilder) error { return b.AddChatCompletion("req-1", chatRequest("again")) }, ErrDuplicateCustomID, ""},
+		{"empty custom_id", func(b *InputBuilder) error { return b.AddChatCompletion("", chatRequest("Hi")) }, nil, "custom_id is required"},
+		{"mixed endpoints", func(b *InputBuilder) error {
+			return b.AddEmbedding("emb-1", &types.CreateEmbeddingRequest{Model: "m", Input: "x"})
+		}, nil, "single endpoint"},
+		{"invalid request", func(b *InputBuilder) error { return b.AddChatCompletion("req-2", &types.CreateChatCompletionRequest{}) }, nil, "model"},
+		{"streaming", func(b *InputBuilder) error { return b.AddChatCompletion("req-2", streaming) }, nil, "streaming"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			b := NewInputBuilder(&buf)
+			if err := b.AddChatCompletion("req-1", chatRequest("Hi")); err != nil {
+				t.Fatalf("AddChatCompletion error: %v", err)
+			}
+			size := buf.Len()
+
+			err := tt.add(b)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantMsg)
+			}
+			if buf.Len() != size || b.Len() != 1 {
+				t.Error("rejected request was written")
+			}
+		})
+	}
+}
+
+func TestInputBuilder_LineLimit(t *testing.T) {
+	b := NewInputBuilder(io.Discard)
+	b.lines = MaxInputLines
+	if err := b.AddChatCompletion("one-too-many", chatRequest("Hi")); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("error = %v, want ErrInputTooLarge", err)
+	}
+
+	b = NewInputBuilder(io.Discard)
+	b.size = MaxInputBytes - 10
+	if err := b.AddChatCompletion("too-big", chatRequest("Hi")); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("error = %v, want ErrInputTooLarge", err)
+	}
+}
+
+func TestInputBuilder_Transcription(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewInputBuilder(&buf)
+
+	if err := b.AddTranscription("audio-1", &types.CreateTranscriptionRequest{File: "a.mp3", Model: "whisper-large-v3"}); err == nil {
+		t.Error("file upload accepted in a batch")
+	}
+
+	err := b.AddTranscription("audio-1", &types.CreateTranscriptionRequest{
+		Model:                  "whisper-large-v3",
+		URL:                    option.Ptr(option.Some("https://example.com/a.mp3")),
+		ResponseFormat:         option.Ptr(option.Some("verbose_json")),
+		TimestampGranularities: []string{"word"},
+	})
+	if err != nil {
+		t.Fatalf("AddTranscription error: %v", err)
+	}
+
+	var line struct {
+		URL  string                 `json:"url"`
+		Body map[string]interface{} `json:"body"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if line.URL != EndpointTranscriptions {
+		t.Errorf("url = %q", line.URL)
+	}
+	if _, ok := line.Body["file"]; ok {
+		t.Error("body contains file")
+	}
+	if g, ok := line.Body["timestamp_granularities"].([]interface{}); !ok || len(g) != 1 || g[0] != "word" {
+		t.Errorf("body = %v", line.Body)
+	}
+}
+
+type mockUploader struct {
+	req     *types.CreateFileRequest
+	content string
+}
+
+func (m *mockUploader) Create(ctx context.Context, req *types.CreateFileRequest, opts ...option.RequestOption) (*types.FileObject, error) {
+	m.req = req
+	data, err := io.ReadAll(req.File.(io.Reader))
+	if err != nil {
+		return nil, err
+	}
+	m.content = string(data)
+	return &types.FileObject{ID: "file-123", Purpose: req.Purpose}, nil
+}
+
+func TestInputBuilder_Upload(t *testing.T) {
+	b, err := NewInputFileBuilder()
+	if err != nil {
+		t.Fatalf("NewInputFileBuilder error: %v", err)
+	}
+	path := b.file.Name()
+
+	if _, err := b.Upload(context.Background(), &mockUploader{}); err == nil {
+		t.Error("empty input uploaded")
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := b.AddChatCompletion(id, chatRequest(id)); err != nil {
+			t.Fatalf("AddChatCompletion error: %v", err)
+		}
+	}
+
+	uploader := &mockUploader{}
+	file, err := b.Upload(context.Background(), uploader)
+	if err != nil {
+		t.Fatalf("Upload error: %v", err)
+	}
+	if file.ID != "file-123" || uploader.req.Purpose != "batch" {
+		t.Errorf("file = %+v, purpose = %q", file, uploader.req.Purpose)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(strings.NewReader(uploader.content))
+	for scanner.Scan() {
+		var line InputLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %d: %v", lines, err)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("uploaded %d lines, want 3", lines)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("temporary file not removed: %v", err)
+	}
+
+	if _, err := NewInputBuilder(io.Discard).Upload(context.Background(), uploader); err == nil {
+		t.Error("writer builder uploaded")
+	}
+}
@@ -0,0 +1,251 @@
+package batches
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// Result is one request of a batch output or error file. Exactly one of
+// ChatCompletion, Embedding and Transcription is set for successful
+// requests; Error is set for failed ones.
+type Result struct {
+	ID         string
+	CustomID   string
+	StatusCode int
+	RequestID  string
+
+	ChatCompletion *types.ChatCompletion
+	Embedding      *types.CreateEmbeddingResponse
+	Transcription  *types.Transcription
+	Error          *types.ErrorObject
+
+	// Body is the raw response body
+	Body json.RawMessage
+}
+
+// Failed reports whether the request failed
+func (r *Result) Failed() bool {
+	return r.Error != nil || r.StatusCode >= 400
+}
+
+// outputLine is the wire format of a batch output or error file line
+type outputLine struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		RequestID  string          `json:"request_id"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *types.ErrorObject `json:"error"`
+}
+
+// FileDownloader downloads file contents; it is implemented by files.Files
+type FileDownloader interface {
+	Content(ctx context.Context, fileID string, opts ...option.RequestOption) (io.ReadCloser, error)
+}
+
+// ResultReader reads batch output and error files line by line
+//
+//	results, err := batches.OpenResults(ctx, client.Files, batch)
+//	defer results.Close()
+//	for {
+//		result, err := results.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		// use result.ChatCompletion or result.Error
+//	}
+type ResultReader struct {
+	endpoint string
+	sources  []io.Reader
+	closers  []io.Closer
+	current  *bufio.Reader
+	line     int
+}
+
+// NewResultReader reads results from r. The endpoint (e.g.
+// EndpointChatCompletions) selects the type bodies are decoded into; if
+// empty, it is inferred from each body.
+func NewResultReader(r io.Reader, endpoint string) *ResultReader {
+	return &ResultReader{endpoint: endpoint, sources: []io.Reader{r}}
+}
+
+// OpenResults downloads the output file and then the error file of a batch,
+// whichever are set
+func OpenResults(ctx context.Context, downloader FileDownloader, batch *types.Batch, opts ...option.RequestOption) (*ResultReader, error) {
+	r := &ResultReader{endpoint: batch.Endpoint}
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		body, err := downloader.Content(ctx, fileID, opts...)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.sources = append(r.sources, body)
+		r.closers = append(r.closers, body)
+	}
+	return r, nil
+}
+
+// Next returns the next result, or io.EOF when all files are read
+func (r *ResultReader) Next() (*Result, error) {
+	for {
+		if r.current == nil {
+			if len(r.sources) == 0 {
+				return nil, io.EOF
+			}
+			r.current = bufio.NewReader(r.sources[0])
+			r.sources = r.sources[1:]
+		}
+
+		data, err := r.current.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if errors.Is(err, io.EOF) {
+			r.current = nil
+		}
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		r.line++
+		result, decodeErr := r.decode(data)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("batches: result line %d: %w", r.line, decodeErr)
+		}
+		return result, nil
+	}
+}
+
+// All reads the remaining results
+func (r *ResultReader) All() ([]*Result, error) {
+	var results []*Result
+	for {
+		result, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+}
+
+// Close closes the downloaded files
+func (r *ResultReader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	r.closers = nil
+	return err
+}
+
+func (r *ResultReader) decode(data []byte) (*Result, error) {
+	var line outputLine
+	if err := json.Unmarshal(data, &line); err != nil {
+		return nil, err
+	}
+
+	result := &Result{ID: line.ID, CustomID: line.CustomID, Error: line.Error}
+	if line.Response == nil {
+		return result, nil
+	}
+	result.StatusCode = line.Response.StatusCode
+	result.RequestID = line.Response.RequestID
+	result.Body = line.Response.Body
+
+	body := bytes.TrimSpace(line.Response.Body)
+	if len(body) == 0 || bytes.Equal(body, []byte("null")) {
+		return result, nil
+	}
+
+	if result.StatusCode >= 400 {
+		var envelope types.ErrorResponse
+		if json.Unmarshal(body, &envelope) == nil && envelope.Error != nil && result.Error == nil {
+			result.Error = envelope.Error
+		}
+		return result, nil
+	}
+
+	endpoint, err := r.endpointOf(body)
+	if err != nil {
+		return nil, err
+	}
+	switch endpoint {
+	case EndpointChatCompletions:
+		result.ChatCompletion = new(types.ChatCompletion)
+		return result, json.Unmarshal(body, result.ChatCompletion)
+	case EndpointEmbeddings:
+		result.Embedding = new(types.CreateEmbeddingResponse)
+		return result, json.Unmarshal(body, result.Embedding)
+	case EndpointTranscriptions, "/v1/audio/translations":
+		result.Transcription = new(types.Transcription)
+		return result, json.Unmarshal(body, result.Transcription)
+	}
+	return result, nil
+}
+
+// endpointOf returns the reader's endpoint, or infers it from the object
+// type of body
+func (r *ResultReader) endpointOf(body []byte) (string, error) {
+	if r.endpoint != "" {
+		return r.endpoint, nil
+	}
+	var probe struct {
+		Object string  `json:"object"`
+		Text   *string `json:"text"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return "", err
+	}
+	switch {
+	case probe.Object == "chat.completion":
+		return EndpointChatCompletions, nil
+	case probe.Object == "list":
+		return EndpointEmbeddings, nil
+	case probe.Text != nil:
+		return EndpointTranscriptions, nil
+	}
+	return "", nil
+}
+
+// JoinedResult pairs a batch input with its result
+type JoinedResult[T any] struct {
+	CustomID string
+	Input    T
+	Result   *Result // Nil if the batch returned no result for the input
+}
+
+// Join pairs inputs, keyed by custom ID, with their results, sorted by
+// custom ID. Results without a matching input are dropped.
+func Join[T any](inputs map[string]T, results []*Result) []JoinedResult[T] {
+	byID := make(map[string]*Result, len(results))
+	for _, result := range results {
+		byID[result.CustomID] = result
+	}
+
+	joined := make([]JoinedResult[T], 0, len(inputs))
+	for id, input := range inputs {
+		joined = append(joined, JoinedResult[T]{CustomID: id, Input: input, Result: byID[id]})
+	}
+	sort.Slice(joined, func(i, j int) bool { return joined[i].CustomID < joined[j].CustomID })
+	return joined
+}
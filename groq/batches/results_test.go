@@ -0,0 +1,152 @@
+package batches
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+const outputJSONL = `{"id":"batch_req_1","custom_id":"req-1","response":{"status_code":200,"request_id":"req_abc","body":{"id":"chatcmpl-1","object":"chat.completion","model":"llama-3.1-8b-instant","choices":[{"index":0,"message":{"role":"assistant","content":"Hello!"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}},"error":null}
+{"id":"batch_req_2","custom_id":"req-2","response":{"status_code":400,"request_id":"req_def","body":{"error":{"message":"context length exceeded","type":"invalid_request_error","code":"context_length_exceeded"}}},"error":null}
+`
+
+const errorJSONL = `{"id":"batch_req_3","custom_id":"req-3","response":null,"error":{"code":"batch_expired","message":"This request could not be executed before the completion window expired."}}
+`
+
+func TestResultReader(t *testing.T) {
+	results, err := NewResultReader(strings.NewReader(outputJSONL+"\n"+errorJSONL), EndpointChatCompletions).All()
+	if err != nil {
+		t.Fatalf("All error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	ok := results[0]
+	if ok.CustomID != "req-1" || ok.StatusCode != 200 || ok.RequestID != "req_abc" || ok.Failed() {
+		t.Errorf("result 0 = %+v", ok)
+	}
+	if ok.ChatCompletion == nil || ok.ChatCompletion.Choices[0].Message.Content != "Hello!" || ok.ChatCompletion.Usage.TotalTokens != 7 {
+		t.Errorf("completion = %+v", ok.ChatCompletion)
+	}
+
+	apiErr := results[1]
+	if !apiErr.Failed() || apiErr.Error == nil || apiErr.Error.Code != "context_length_exceeded" || apiErr.ChatCompletion != nil {
+		t.Errorf("result 1 = %+v", apiErr)
+	}
+
+	expired := results[2]
+	if !expired.Failed() || expired.Error.Code != "batch_expired" || expired.StatusCode != 0 {
+		t.Errorf("result 2 = %+v", expired)
+	}
+}
+
+func TestResultReader_InferEndpoint(t *testing.T) {
+	input := `{"custom_id":"emb","response":{"status_code":200,"body":{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.1,0.2]}],"model":"m"}}}
+{"custom_id":"audio","response":{"status_code":200,"body":{"text":"hello world"}}}
+`
+	results, err := NewResultReader(strings.NewReader(input), "").All()
+	if err != nil {
+		t.Fatalf("All error: %v", err)
+	}
+	if results[0].Embedding == nil || len(results[0].Embedding.Data) != 1 {
+		t.Errorf("embedding = %+v", results[0])
+	}
+	if results[1].Transcription == nil || results[1].Transcription.Text != "hello world" {
+		t.Errorf("transcription = %+v", results[1])
+	}
+}
+
+func TestResultReader_InferEndpointInvalidBody(t *testing.T) {
+	input := `{"custom_id":"bad","response":{"status_code":200,"body":{"object":42}}}
+`
+	_, err := NewResultReader(strings.NewReader(input), "").Next()
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("error = %v, want line 1 decode error", err)
+	}
+}
+
+func TestResultReader_InvalidLine(t *testing.T) {
+	r := NewResultReader(strings.NewReader(outputJSONL+"not json\n"), EndpointChatCompletions)
+	r.Next()
+	r.Next()
+	if _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("error = %v, want line 3 error", err)
+	}
+}
+
+type mockDownloader struct {
+	files  map[string]string
+	closed int
+}
+
+type trackedBody struct {
+	io.Reader
+	d *mockDownloader
+}
+
+func (b trackedBody) Close() error {
+	b.d.closed++
+	return nil
+}
+
+func (m *mockDownloader) Content(ctx context.Context, fileID string, opts ...option.RequestOption) (io.ReadCloser, error) {
+	content, ok := m.files[fileID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return trackedBody{Reader: strings.NewReader(content), d: m}, nil
+}
+
+func TestOpenResults(t *testing.T) {
+	downloader := &mockDownloader{files: map[string]string{"file-out": outputJSONL, "file-err": errorJSONL}}
+	batch := &types.Batch{Endpoint: EndpointChatCompletions, OutputFileID: "file-out", ErrorFileID: "file-err"}
+
+	r, err := OpenResults(context.Background(), downloader, batch)
+	if err != nil {
+		t.Fatalf("OpenResults error: %v", err)
+	}
+	results, err := r.All()
+	if err != nil {
+		t.Fatalf("All error: %v", err)
+	}
+	if len(results) != 3 || results[2].CustomID != "req-3" {
+		t.Errorf("results = %d", len(results))
+	}
+	r.Close()
+	if downloader.closed != 2 {
+		t.Errorf("closed %d files, want 2", downloader.closed)
+	}
+
+	// A failed download closes the files already opened
+	downloader = &mockDownloader{files: map[string]string{"file-out": outputJSONL}}
+	if _, err := OpenResults(context.Background(), downloader, batch); err == nil || downloader.closed != 1 {
+		t.Errorf("error = %v, closed = %d", err, downloader.closed)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	inputs := map[string]string{"req-1": "Hello", "req-2": "World", "req-4": "Missing"}
+	results, _ := NewResultReader(strings.NewReader(outputJSONL+errorJSONL), EndpointChatCompletions).All()
+
+	joined := Join(inputs, results)
+	if len(joined) != 3 {
+		t.Fatalf("joined %d, want 3", len(joined))
+	}
+	if joined[0].CustomID != "req-1" || joined[0].Input != "Hello" || joined[0].Result.ChatCompletion == nil {
+		t.Errorf("joined[0] = %+v", joined[0])
+	}
+	if joined[1].Result == nil || !joined[1].Result.Failed() {
+		t.Errorf("joined[1] = %+v", joined[1])
+	}
+	if joined[2].CustomID != "req-4" || joined[2].Result != nil {
+		t.Errorf("joined[2] = %+v", joined[2])
+	}
+}
@@ -0,0 +1,247 @@
+package batches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// Default polling intervals of Wait
+const (
+	DefaultPollInterval    = 5 * time.Second
+	DefaultMaxPollInterval = time.Minute
+)
+
+// DefaultCompletionWindow is the completion window RunBatch uses by default
+const DefaultCompletionWindow = "24h"
+
+type waitConfig struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	multiplier  float64
+	progress    func(*types.Batch)
+	requestOpts []option.RequestOption
+}
+
+// WaitOption configures Wait and RunBatch
+type WaitOption func(*waitConfig)
+
+// WithPollInterval sets the first polling interval (default 5s)
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(c *waitConfig) { c.interval = d }
+}
+
+// WithMaxPollInterval caps the polling interval (default 1m)
+func WithMaxPollInterval(d time.Duration) WaitOption {
+	return func(c *waitConfig) { c.maxInterval = d }
+}
+
+// WithPollBackoff multiplies the polling interval by factor after each poll
+// that shows no progress (default 1.5). Progress resets the interval.
+func WithPollBackoff(factor float64) WaitOption {
+	return func(c *waitConfig) { c.multiplier = factor }
+}
+
+// WithProgress calls fn with the batch whenever its status or request counts
+// change
+func WithProgress(fn func(*types.Batch)) WaitOption {
+	return func(c *waitConfig) { c.progress = fn }
+}
+
+// WithWaitRequestOptions applies opts to every request made while waiting
+func WithWaitRequestOptions(opts ...option.RequestOption) WaitOption {
+	return func(c *waitConfig) { c.requestOpts = append(c.requestOpts, opts...) }
+}
+
+// StatusError is returned when a batch ended without completing
+type StatusError struct {
+	Batch *types.Batch
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("batches: batch %s %s", e.Batch.ID, e.Batch.Status)
+	if e.Batch.Errors != nil && len(e.Batch.Errors.Data) > 0 {
+		msg += ": " + e.Batch.Errors.Data[0].Message
+	}
+	return msg
+}
+
+// Wait polls a batch until it reaches a terminal status and returns it. The
+// interval grows while the batch makes no progress. It returns the last
+// retrieved batch with ctx.Err() if ctx is done first.
+func (b *Batches) Wait(ctx context.Context, batchID string, opts ...WaitOption) (*types.Batch, error) {
+	cfg := newWaitConfig(opts)
+
+	var last *types.Batch
+	interval := cfg.interval
+	for {
+		batch, err := b.Retrieve(ctx, batchID, cfg.requestOpts...)
+		if err != nil {
+			return last, err
+		}
+
+		if madeProgress(last, batch) {
+			interval = cfg.interval
+			if cfg.progress != nil {
+				cfg.progress(batch)
+			}
+		} else {
+			interval = min(time.Duration(float64(interval)*cfg.multiplier), cfg.maxInterval)
+		}
+		last = batch
+
+		if batch.Status.IsTerminal() {
+			return batch, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func newWaitConfig(opts []WaitOption) waitConfig {
+	cfg := waitConfig{
+		interval:    DefaultPollInterval,
+		maxInterval: DefaultMaxPollInterval,
+		multiplier:  1.5,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.maxInterval < cfg.interval {
+		cfg.maxInterval = cfg.interval
+	}
+	if cfg.multiplier < 1 {
+		cfg.multiplier = 1
+	}
+	return cfg
+}
+
+func madeProgress(prev, next *types.Batch) bool {
+	if prev == nil || prev.Status != next.Status {
+		return true
+	}
+	if (prev.RequestCounts == nil) != (next.RequestCounts == nil) {
+		return true
+	}
+	return prev.RequestCounts != nil && *prev.RequestCounts != *next.RequestCounts
+}
+
+// FileService uploads batch input and downloads batch results; it is
+// implemented by files.Files
+type FileService interface {
+	FileUploader
+	FileDownloader
+}
+
+// RunRequest describes a batch for RunBatch
+type RunRequest struct {
+	// Build adds the requests of the batch
+	Build            func(*InputBuilder) error
+	CompletionWindow string // DefaultCompletionWindow if empty
+	Metadata         map[string]string
+}
+
+// RunResult is the outcome of RunBatch
+type RunResult struct {
+	Batch   *types.Batch
+	Results []*Result // Output and error file results
+}
+
+// RunBatch builds the input file, uploads it, creates the batch, waits for
+// it and reads its results. Expired batches return the results that
+// completed in time; failed and cancelled batches return a *StatusError. On
+// error the returned result holds the batch, if it was created, so it can be
+// resumed with Wait. A nil req, Build or files is an error before anything
+// is uploaded.
+//
+//	result, err := client.Batches.RunBatch(ctx, client.Files, &batches.RunRequest{
+//		Build: func(input *batches.InputBuilder) error {
+//			return input.AddChatCompletion("req-1", req)
+//		},
+//	})
+func (b *Batches) RunBatch(ctx context.Context, files FileService, req *RunRequest, opts ...WaitOption) (*RunResult, error) {
+	switch {
+	case req == nil:
+		return nil, errors.New("batches: run request is required")
+	case req.Build == nil:
+		return nil, fmt.Errorf("batches: %w", &types.FieldError{Field: "Build", Message: "is required"})
+	case isNil(files):
+		return nil, errors.New("batches: file service is required")
+	}
+	cfg := newWaitConfig(opts)
+
+	input, err := NewInputFileBuilder()
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+
+	if err := req.Build(input); err != nil {
+		return nil, err
+	}
+	file, err := input.Upload(ctx, files, cfg.requestOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("batches: upload input: %w", err)
+	}
+
+	window := req.CompletionWindow
+	if window == "" {
+		window = DefaultCompletionWindow
+	}
+	batch, err := b.Create(ctx, &types.CreateBatchRequest{
+		InputFileID:      file.ID,
+		Endpoint:         input.Endpoint(),
+		CompletionWindow: window,
+		Metadata:         req.Metadata,
+	}, cfg.requestOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RunResult{Batch: batch}
+	if batch, err = b.Wait(ctx, batch.ID, opts...); batch != nil {
+		result.Batch = batch
+	}
+	if err != nil {
+		return result, err
+	}
+
+	switch result.Batch.Status {
+	case types.BatchStatusCompleted, types.BatchStatusExpired:
+	default:
+		return result, &StatusError{Batch: result.Batch}
+	}
+
+	reader, err := OpenResults(ctx, files, result.Batch, cfg.requestOpts...)
+	if err != nil {
+		return result, err
+	}
+	defer reader.Close()
+	result.Results, err = reader.All()
+	return result, err
+}
+
+// isNil reports whether v is nil or an interface holding a nil pointer, such
+// as a nil *files.Files
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
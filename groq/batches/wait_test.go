@@ -0,0 +1,215 @@
+package batches
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func TestBatchStatus_IsTerminal(t *testing.T) {
+	terminal := map[types.BatchStatus]bool{
+		types.BatchStatusValidating: false,
+		types.BatchStatusInProgress: false,
+		types.BatchStatusFinalizing: false,
+		types.BatchStatusCancelling: false,
+		types.BatchStatusCompleted:  true,
+		types.BatchStatusFailed:     true,
+		types.BatchStatusExpired:    true,
+		types.BatchStatusCancelled:  true,
+	}
+	for status, want := range terminal {
+		if got := status.IsTerminal(); got != want {
+			t.Errorf("%s.IsTerminal() = %v, want %v", status, got, want)
+		}
+	}
+}
+
+// pollSequence returns a requester whose Retrieve responses walk through
+// batches, repeating the last one
+func pollSequence(batches []types.Batch, polls *int) *mockRequester {
+	return &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			i := min(*polls, len(batches)-1)
+			*polls++
+			*result.(*types.Batch) = batches[i]
+			return nil
+		},
+	}
+}
+
+func TestBatches_Wait(t *testing.T) {
+	counts := func(completed int) *types.BatchRequestCounts {
+		return &types.BatchRequestCounts{Total: 2, Completed: completed}
+	}
+	sequence := []types.Batch{
+		{ID: "batch_1", Status: types.BatchStatusValidating},
+		{ID: "batch_1", Status: types.BatchStatusInProgress, RequestCounts: counts(0)},
+		{ID: "batch_1", Status: types.BatchStatusInProgress, RequestCounts: counts(0)},
+		{ID: "batch_1", Status: types.BatchStatusInProgress, RequestCounts: counts(1)},
+		{ID: "batch_1", Status: types.BatchStatusCompleted, RequestCounts: counts(2)},
+	}
+
+	polls := 0
+	var progress []string
+	b := New(pollSequence(sequence, &polls))
+	batch, err := b.Wait(context.Background(), "batch_1",
+		WithPollInterval(time.Millisecond),
+		WithProgress(func(batch *types.Batch) {
+			completed := 0
+			if batch.RequestCounts != nil {
+				completed = batch.RequestCounts.Completed
+			}
+			progress = append(progress, string(batch.Status)+":"+string(rune('0'+completed)))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if batch.Status != types.BatchStatusCompleted || polls != 5 {
+		t.Errorf("status = %s after %d polls", batch.Status, polls)
+	}
+	// The unchanged third poll is not reported
+	if got := strings.Join(progress, ","); got != "validating:0,in_progress:0,in_progress:1,completed:2" {
+		t.Errorf("progress = %s", got)
+	}
+}
+
+func TestBatches_WaitContext(t *testing.T) {
+	polls := 0
+	b := New(pollSequence([]types.Batch{{ID: "batch_1", Status: types.BatchStatusInProgress}}, &polls))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	batch, err := b.Wait(ctx, "batch_1", WithPollInterval(time.Millisecond), WithMaxPollInterval(5*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+	if batch == nil || batch.Status != types.BatchStatusInProgress {
+		t.Errorf("last batch = %+v", batch)
+	}
+}
+
+func TestWaitConfig_Backoff(t *testing.T) {
+	cfg := newWaitConfig([]WaitOption{WithPollInterval(time.Second), WithMaxPollInterval(3 * time.Second), WithPollBackoff(2)})
+	if cfg.interval != time.Second || cfg.maxInterval != 3*time.Second || cfg.multiplier != 2 {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	cfg = newWaitConfig([]WaitOption{WithPollInterval(time.Hour), WithPollBackoff(0.5)})
+	if cfg.maxInterval != time.Hour || cfg.multiplier != 1 {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+type mockFiles struct {
+	mockUploader
+	mockDownloader
+}
+
+func TestBatches_RunBatch(t *testing.T) {
+	files := &mockFiles{mockDownloader: mockDownloader{files: map[string]string{"file-out": outputJSONL}}}
+
+	var created *types.CreateBatchRequest
+	polls := 0
+	mock := pollSequence([]types.Batch{
+		{ID: "batch_1", Status: types.BatchStatusInProgress},
+		{ID: "batch_1", Status: types.BatchStatusCompleted, Endpoint: EndpointChatCompletions, OutputFileID: "file-out"},
+	}, &polls)
+	mock.postFunc = func(ctx context.Context, path string, body, result interface{}, opts ...option.RequestOption) error {
+		created = body.(*types.CreateBatchRequest)
+		*result.(*types.Batch) = types.Batch{ID: "batch_1", Status: types.BatchStatusValidating}
+		return nil
+	}
+
+	result, err := New(mock).RunBatch(context.Background(), files, &RunRequest{
+		Build: func(input *InputBuilder) error {
+			if err := input.AddChatCompletion("req-1", chatRequest("Hello")); err != nil {
+				return err
+			}
+			return input.AddChatCompletion("req-2", chatRequest("World"))
+		},
+		Metadata: map[string]string{"job": "test"},
+	}, WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("RunBatch error: %v", err)
+	}
+
+	if created.InputFileID != "file-123" || created.Endpoint != EndpointChatCompletions || created.CompletionWindow != "24h" || created.Metadata["job"] != "test" {
+		t.Errorf("create request = %+v", created)
+	}
+	if strings.Count(files.content, "\n") != 2 {
+		t.Errorf("uploaded input = %q", files.content)
+	}
+	if result.Batch.Status != types.BatchStatusCompleted || len(result.Results) != 2 {
+		t.Errorf("result = %+v with %d results", result.Batch, len(result.Results))
+	}
+}
+
+func TestBatches_RunBatchFailed(t *testing.T) {
+	polls := 0
+	mock := pollSequence([]types.Batch{{
+		ID:     "batch_1",
+		Status: types.BatchStatusFailed,
+		Errors: &types.BatchErrors{Data: []types.ErrorObject{{Message: "invalid model"}}},
+	}}, &polls)
+	mock.postFunc = func(ctx context.Context, path string, body, result interface{}, opts ...option.RequestOption) error {
+		*result.(*types.Batch) = types.Batch{ID: "batch_1"}
+		return nil
+	}
+
+	result, err := New(mock).RunBatch(context.Background(), &mockFiles{}, &RunRequest{
+		Build: func(input *InputBuilder) error { return input.AddChatCompletion("req-1", chatRequest("Hi")) },
+	}, WithPollInterval(time.Millisecond))
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || !strings.Contains(err.Error(), "invalid model") {
+		t.Fatalf("error = %v, want StatusError", err)
+	}
+	if result == nil || result.Batch.Status != types.BatchStatusFailed {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestBatches_RunBatchBuildError(t *testing.T) {
+	_, err := New(&mockRequester{}).RunBatch(context.Background(), &mockFiles{}, &RunRequest{
+		Build: func(input *InputBuilder) error { return io.ErrUnexpectedEOF },
+	})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestBatches_RunBatchInvalid(t *testing.T) {
+	var builds int
+	files := &mockFiles{}
+	b := New(&mockRequester{})
+
+	tests := []struct {
+		name  string
+		files FileService
+		req   *RunRequest
+		want  string
+	}{
+		{"nil request", files, nil, "run request is required"},
+		{"nil build", files, &RunRequest{CompletionWindow: "24h"}, "Build: is required"},
+		{"nil files", nil, &RunRequest{Build: func(*InputBuilder) error { builds++; return nil }}, "file service is required"},
+		{"typed nil files", (*mockFiles)(nil), &RunRequest{Build: func(*InputBuilder) error { builds++; return nil }}, "file service is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := b.RunBatch(context.Background(), tt.files, tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if builds != 0 {
+		t.Errorf("Build called %d times", builds)
+	}
+}
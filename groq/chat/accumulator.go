@@ -0,0 +1,233 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// ChatCompletionAccumulator rebuilds a complete ChatCompletion from streamed
+// chunks, so streaming and non-streaming code can share result handling.
+// The zero value is ready to use.
+//
+//	var acc chat.ChatCompletionAccumulator
+//	for {
+//		chunk, err := stream.Next(ctx)
+//		if err != nil {
+//			break
+//		}
+//		acc.AddChunk(chunk)
+//		// use chunk deltas for display
+//	}
+//	completion := acc.ChatCompletion()
+type ChatCompletionAccumulator struct {
+	completion types.ChatCompletion
+
+	// Tool call position in Message.ToolCalls by choice and delta index
+	toolCalls map[int]map[int]int
+	streamErr error
+}
+
+// AddChunk merges a chunk into the accumulated completion
+func (a *ChatCompletionAccumulator) AddChunk(chunk *types.ChatCompletionChunk) {
+	if chunk == nil {
+		return
+	}
+
+	c := &a.completion
+	c.Object = "chat.completion"
+	if chunk.ID != "" {
+		c.ID = chunk.ID
+	}
+	if chunk.Created != 0 {
+		c.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		c.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != "" {
+		c.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.Usage != nil {
+		c.Usage = chunk.Usage
+	}
+
+	if x := chunk.XGroq; x != nil {
+		if c.XGroq == nil {
+			c.XGroq = &types.XGroq{}
+		}
+		if x.ID != nil {
+			c.XGroq.ID = *x.ID
+		}
+		if x.Debug != nil {
+			c.XGroq.Debug = x.Debug
+		}
+		if x.Seed != nil {
+			c.XGroq.Seed = x.Seed
+		}
+		if x.Usage != nil && chunk.Usage == nil {
+			c.Usage = x.Usage
+		}
+		if x.UsageBreakdown != nil {
+			c.UsageBreakdown = x.UsageBreakdown
+		}
+		if x.Error != nil && a.streamErr == nil {
+			a.streamErr = fmt.Errorf("stream stopped early: %s", *x.Error)
+		}
+	}
+
+	for i := range chunk.Choices {
+		a.addChoice(&chunk.Choices[i])
+	}
+}
+
+func (a *ChatCompletionAccumulator) addChoice(delta *types.ChatCompletionChunkChoice) {
+	choice := a.choice(delta.Index)
+	msg := &choice.Message
+	d := &delta.Delta
+
+	if delta.FinishReason != "" {
+		choice.FinishReason = delta.FinishReason
+	}
+	if delta.Logprobs != nil {
+		if choice.Logprobs == nil {
+			choice.Logprobs = &types.ChatCompletionLogprobs{}
+		}
+		choice.Logprobs.Content = append(choice.Logprobs.Content, delta.Logprobs.Content...)
+	}
+
+	if d.Role != "" {
+		msg.Role = d.Role
+	}
+	msg.Content += d.Content
+	msg.Refusal += d.Refusal
+	if d.Reasoning != nil {
+		reasoning := *d.Reasoning
+		if msg.Reasoning != nil {
+			reasoning = *msg.Reasoning + reasoning
+		}
+		msg.Reasoning = &reasoning
+	}
+	if d.FunctionCall != nil {
+		if msg.FunctionCall == nil {
+			msg.FunctionCall = &types.FunctionCall{}
+		}
+		msg.FunctionCall.Name += d.FunctionCall.Name
+		msg.FunctionCall.Arguments += d.FunctionCall.Arguments
+	}
+	msg.Annotations = append(msg.Annotations, d.Annotations...)
+
+	for i, tc := range d.ToolCalls {
+		index := i
+		if tc.Index != nil {
+			index = *tc.Index
+		}
+		a.addToolCall(choice.Index, msg, index, &tc)
+	}
+
+	for _, tool := range d.ExecutedTools {
+		addExecutedTool(msg, tool)
+	}
+}
+
+// choice returns the accumulated choice with the given index, adding it and
+// any missing lower indexes
+func (a *ChatCompletionAccumulator) choice(index int) *types.ChatCompletionChoice {
+	for len(a.completion.Choices) <= index {
+		a.completion.Choices = append(a.completion.Choices, types.ChatCompletionChoice{
+			Index:   len(a.completion.Choices),
+			Message: types.ChatCompletionMessage{Role: types.RoleAssistant},
+		})
+	}
+	return &a.completion.Choices[index]
+}
+
+func (a *ChatCompletionAccumulator) addToolCall(choice int, msg *types.ChatCompletionMessage, index int, delta *types.ToolCall) {
+	if a.toolCalls == nil {
+		a.toolCalls = make(map[int]map[int]int)
+	}
+	positions := a.toolCalls[choice]
+	if positions == nil {
+		positions = make(map[int]int)
+		a.toolCalls[choice] = positions
+	}
+
+	pos, ok := positions[index]
+	if !ok {
+		pos = len(msg.ToolCalls)
+		positions[index] = pos
+		msg.ToolCalls = append(msg.ToolCalls, types.ToolCall{Type: "function"})
+	}
+
+	// Complete tool calls match non-streaming responses, which have no index
+	call := &msg.ToolCalls[pos]
+	if delta.ID != "" {
+		call.ID = delta.ID
+	}
+	if delta.Type != "" {
+		call.Type = delta.Type
+	}
+	call.Function.Name += delta.Function.Name
+	call.Function.Arguments += delta.Function.Arguments
+}
+
+// addExecutedTool merges a server-side tool into the one with the same
+// index: tools are announced with their arguments and repeated with output
+func addExecutedTool(msg *types.ChatCompletionMessage, tool types.ExecutedTool) {
+	for i := range msg.ExecutedTools {
+		existing := &msg.ExecutedTools[i]
+		if existing.Index != tool.Index {
+			continue
+		}
+		if tool.Type != "" {
+			existing.Type = tool.Type
+		}
+		if tool.Arguments != "" {
+			existing.Arguments = tool.Arguments
+		}
+		if tool.Output != nil {
+			existing.Output = tool.Output
+		}
+		if tool.BrowserResults != nil {
+			existing.BrowserResults = tool.BrowserResults
+		}
+		if tool.CodeResults != nil {
+			existing.CodeResults = tool.CodeResults
+		}
+		if tool.SearchResults != nil {
+			existing.SearchResults = tool.SearchResults
+		}
+		return
+	}
+	msg.ExecutedTools = append(msg.ExecutedTools, tool)
+}
+
+// ChatCompletion returns the completion accumulated so far
+func (a *ChatCompletionAccumulator) ChatCompletion() *types.ChatCompletion {
+	return &a.completion
+}
+
+// Err returns the error reported in x_groq if the stream stopped early
+func (a *ChatCompletionAccumulator) Err() error {
+	return a.streamErr
+}
+
+// Accumulate reads the stream to the end and returns the complete
+// completion. The caller still closes the stream. If reading fails, the
+// partial completion is returned with the error.
+func Accumulate(ctx context.Context, stream *Stream[types.ChatCompletionChunk]) (*types.ChatCompletion, error) {
+	var acc ChatCompletionAccumulator
+	for {
+		chunk, err := stream.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return acc.ChatCompletion(), acc.Err()
+		}
+		if err != nil {
+			return acc.ChatCompletion(), err
+		}
+		acc.AddChunk(chunk)
+	}
+}
@@ -0,0 +1,157 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func streamFromEvents(events ...string) *Stream[types.ChatCompletionChunk] {
+	var sb strings.Builder
+	for _, e := range events {
+		sb.WriteString("data: " + e + "\n\n")
+	}
+	sb.WriteString("data: [DONE]\n\n")
+	resp := &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader(sb.String())),
+		Header:     make(http.Header),
+	}
+	return NewStream[types.ChatCompletionChunk](resp)
+}
+
+func TestAccumulate_Content(t *testing.T) {
+	stream := streamFromEvents(
+		`{"id":"chatcmpl-1","created":1700000000,"model":"llama","x_groq":{"id":"req_1"},"choices":[{"index":0,"delta":{"role":"assistant","reasoning":"Think"}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"reasoning":"ing","content":"Hel"}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"lo","annotations":[{"type":"document_citation","document_citation":{"document_id":"d1"}}]},"finish_reason":"stop"}],"x_groq":{"id":"req_1","seed":7,"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}}`,
+	)
+	defer stream.Close()
+
+	got, err := Accumulate(context.Background(), stream)
+	if err != nil {
+		t.Fatalf("Accumulate error: %v", err)
+	}
+
+	if got.ID != "chatcmpl-1" || got.Model != "llama" || got.Created != 1700000000 || got.Object != "chat.completion" {
+		t.Errorf("metadata = %+v", got)
+	}
+	if len(got.Choices) != 1 {
+		t.Fatalf("got %d choices, want 1", len(got.Choices))
+	}
+	msg := got.Choices[0].Message
+	if msg.Role != types.RoleAssistant || msg.Content != "Hello" {
+		t.Errorf("message = %+v", msg)
+	}
+	if msg.Reasoning == nil || *msg.Reasoning != "Thinking" {
+		t.Errorf("reasoning = %v", msg.Reasoning)
+	}
+	if len(msg.Annotations) != 1 {
+		t.Errorf("annotations = %+v", msg.Annotations)
+	}
+	if got.Choices[0].FinishReason != types.FinishReasonStop {
+		t.Errorf("finish reason = %q", got.Choices[0].FinishReason)
+	}
+	if got.Usage == nil || got.Usage.TotalTokens != 5 {
+		t.Errorf("usage = %+v", got.Usage)
+	}
+	if got.XGroq == nil || got.XGroq.ID != "req_1" || got.XGroq.Seed == nil || *got.XGroq.Seed != 7 {
+		t.Errorf("x_groq = %+v", got.XGroq)
+	}
+}
+
+func TestAccumulate_ToolCalls(t *testing.T) {
+	stream := streamFromEvents(
+		`{"id":"c","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"id":"c","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+		`{"id":"c","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+		`{"id":"c","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]},"finish_reason":"tool_calls"}]}`,
+	)
+	defer stream.Close()
+
+	got, err := Accumulate(context.Background(), stream)
+	if err != nil {
+		t.Fatalf("Accumulate error: %v", err)
+	}
+
+	calls := got.Choices[0].Message.ToolCalls
+	if len(calls) != 2 {
+		t.Fatalf("got %d tool calls, want 2", len(calls))
+	}
+	want := []types.ToolCall{
+		{ID: "call_a", Type: "function", Function: types.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		{ID: "call_b", Type: "function", Function: types.FunctionCall{Name: "get_time", Arguments: `{}`}},
+	}
+	for i := range want {
+		if calls[i].ID != want[i].ID || calls[i].Type != want[i].Type || calls[i].Function != want[i].Function {
+			t.Errorf("tool call %d = %+v, want %+v", i, calls[i], want[i])
+		}
+		if calls[i].Index != nil {
+			t.Errorf("tool call %d has index %d, want none as in non-streaming responses", i, *calls[i].Index)
+		}
+	}
+	if !json.Valid([]byte(calls[0].Function.Arguments)) {
+		t.Errorf("arguments not valid JSON: %s", calls[0].Function.Arguments)
+	}
+	if got.Choices[0].FinishReason != types.FinishReasonToolCalls {
+		t.Errorf("finish reason = %q", got.Choices[0].FinishReason)
+	}
+}
+
+func TestAccumulator_ExecutedToolsAndChoices(t *testing.T) {
+	var acc ChatCompletionAccumulator
+	output := "42"
+	chunks := []types.ChatCompletionChunk{
+		{Choices: []types.ChatCompletionChunkChoice{{Index: 1, Delta: types.ChatCompletionChunkDelta{Content: "second"}}}},
+		{Choices: []types.ChatCompletionChunkChoice{{Index: 0, Delta: types.ChatCompletionChunkDelta{
+			ExecutedTools: []types.ExecutedTool{{Index: 0, Type: "python", Arguments: `{"code":"6*7"}`}},
+		}}}},
+		{Choices: []types.ChatCompletionChunkChoice{{Index: 0, Delta: types.ChatCompletionChunkDelta{
+			ExecutedTools: []types.ExecutedTool{{Index: 0, Output: &output}},
+			Content:       "first",
+		}}}},
+	}
+	for i := range chunks {
+		acc.AddChunk(&chunks[i])
+	}
+
+	got := acc.ChatCompletion()
+	if len(got.Choices) != 2 {
+		t.Fatalf("got %d choices, want 2", len(got.Choices))
+	}
+	if got.Choices[0].Index != 0 || got.Choices[0].Message.Content != "first" {
+		t.Errorf("choice 0 = %+v", got.Choices[0])
+	}
+	if got.Choices[1].Index != 1 || got.Choices[1].Message.Content != "second" {
+		t.Errorf("choice 1 = %+v", got.Choices[1])
+	}
+
+	tools := got.Choices[0].Message.ExecutedTools
+	if len(tools) != 1 {
+		t.Fatalf("got %d executed tools, want 1", len(tools))
+	}
+	if tools[0].Type != "python" || tools[0].Arguments != `{"code":"6*7"}` || tools[0].Output == nil || *tools[0].Output != "42" {
+		t.Errorf("executed tool = %+v", tools[0])
+	}
+}
+
+func TestAccumulate_StreamError(t *testing.T) {
+	stream := streamFromEvents(
+		`{"id":"c","choices":[{"index":0,"delta":{"content":"partial"}}]}`,
+		`{"id":"c","choices":[],"x_groq":{"error":"model overloaded"}}`,
+	)
+	defer stream.Close()
+
+	got, err := Accumulate(context.Background(), stream)
+	if err == nil || !strings.Contains(err.Error(), "model overloaded") {
+		t.Fatalf("error = %v, want x_groq error", err)
+	}
+	if got.Choices[0].Message.Content != "partial" {
+		t.Errorf("partial content = %q", got.Choices[0].Message.Content)
+	}
+}
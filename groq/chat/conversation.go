@@ -0,0 +1,311 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ZaguanLabs/groq-go/groq/models"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// DefaultContextWindow is the context window assumed for models missing
+// from the models capability registry
+const DefaultContextWindow = 8192
+
+// ContextWindow returns the context window of model in tokens, as recorded
+// by models.Capabilities
+func ContextWindow(model string) int {
+	if caps, ok := models.Capabilities(types.ModelID(model)); ok && caps.ContextWindow > 0 {
+		return caps.ContextWindow
+	}
+	return DefaultContextWindow
+}
+
+// ErrContextWindowExceeded is returned when a conversation cannot be trimmed
+// to fit the context window, e.g. because the latest message alone is too
+// large
+var ErrContextWindowExceeded = errors.New("chat: conversation exceeds the context window")
+
+// TruncationStrategy decides which messages a Conversation drops when it
+// exceeds its token budget. The latest message is never dropped, and tool
+// results are dropped together with the assistant message that requested
+// them.
+type TruncationStrategy int
+
+const (
+	// TruncateKeepSystem drops the oldest messages after the leading system
+	// messages
+	TruncateKeepSystem TruncationStrategy = iota
+	// TruncateDropOldest drops the oldest messages, including system messages
+	TruncateDropOldest
+	// TruncateSummarize replaces the oldest messages after the leading system
+	// messages with a summary produced by the SummarizeFunc
+	TruncateSummarize
+)
+
+// SummarizeFunc condenses dropped messages into a summary. A previous
+// summary, if any, is passed as the first message so it can be folded in.
+type SummarizeFunc func(ctx context.Context, messages []types.ChatCompletionMessageParam) (string, error)
+
+// Conversation stores the message history of a chat and trims it to the
+// model's context window. It is not safe for concurrent use.
+//
+//	conv := chat.NewConversation(string(types.ModelLlama33_70BVersatile),
+//		chat.WithSystemPrompt("You are a helpful assistant."))
+//	conv.AppendUser("Hello!")
+//	req, err := conv.Request(ctx, &types.CreateChatCompletionRequest{})
+//	completion, err := client.Chat.Create(ctx, req)
+//	conv.AppendCompletion(completion)
+type Conversation struct {
+	model         string
+	contextWindow int
+	reserve       int
+	strategy      TruncationStrategy
+	summarize     SummarizeFunc
+
+	messages []types.ChatCompletionMessageParam
+	summary  string // Summary of dropped messages, sent after the system messages
+}
+
+// ConversationOption configures a Conversation
+type ConversationOption func(*Conversation)
+
+// WithSystemPrompt starts the conversation with a system message
+func WithSystemPrompt(prompt string) ConversationOption {
+	return func(c *Conversation) {
+		c.messages = append(c.messages, types.ChatCompletionMessageParam{Role: types.RoleSystem, Content: prompt})
+	}
+}
+
+// WithContextWindow overrides the context window of the model
+func WithContextWindow(tokens int) ConversationOption {
+	return func(c *Conversation) { c.contextWindow = tokens }
+}
+
+// WithReservedTokens keeps n tokens of the context window free for the reply
+// when the request does not set max_completion_tokens or max_tokens
+func WithReservedTokens(n int) ConversationOption {
+	return func(c *Conversation) { c.reserve = n }
+}
+
+// WithTruncation sets the truncation strategy (default TruncateKeepSystem)
+func WithTruncation(strategy TruncationStrategy) ConversationOption {
+	return func(c *Conversation) { c.strategy = strategy }
+}
+
+// WithSummarizer enables TruncateSummarize with fn
+func WithSummarizer(fn SummarizeFunc) ConversationOption {
+	return func(c *Conversation) {
+		c.strategy = TruncateSummarize
+		c.summarize = fn
+	}
+}
+
+// NewConversation creates an empty conversation for model
+func NewConversation(model string, opts ...ConversationOption) *Conversation {
+	c := &Conversation{model: model}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.contextWindow <= 0 {
+		c.contextWindow = ContextWindow(model)
+	}
+	return c
+}
+
+// Append adds messages to the end of the conversation
+func (c *Conversation) Append(messages ...types.ChatCompletionMessageParam) {
+	c.messages = append(c.messages, messages...)
+}
+
+// AppendUser adds a user message; content is a string or []ContentPart
+func (c *Conversation) AppendUser(content interface{}) {
+	c.Append(types.ChatCompletionMessageParam{Role: types.RoleUser, Content: content})
+}
+
+// AppendToolResult adds the result of a tool call
+func (c *Conversation) AppendToolResult(toolCallID, content string) {
+	c.Append(types.ChatCompletionMessageParam{Role: types.RoleTool, ToolCallID: toolCallID, Content: content})
+}
+
+// AppendCompletion adds the first choice of completion as an assistant
+// message, keeping its tool calls and reasoning
+func (c *Conversation) AppendCompletion(completion *types.ChatCompletion) {
+	if completion == nil || len(completion.Choices) == 0 {
+		return
+	}
+	msg := completion.Choices[0].Message
+
+	param := types.ChatCompletionMessageParam{
+		Role:         types.RoleAssistant,
+		FunctionCall: msg.FunctionCall,
+		Reasoning:    msg.Reasoning,
+	}
+	if msg.Content != "" || (len(msg.ToolCalls) == 0 && msg.FunctionCall == nil) {
+		param.Content = msg.Content
+	}
+	for _, call := range msg.ToolCalls {
+		call.Index = nil
+		param.ToolCalls = append(param.ToolCalls, call)
+	}
+	c.Append(param)
+}
+
+// Messages returns the conversation as sent to the model: the leading system
+// messages, the summary of dropped messages if any, then the rest
+func (c *Conversation) Messages() []types.ChatCompletionMessageParam {
+	if c.summary == "" {
+		return append([]types.ChatCompletionMessageParam(nil), c.messages...)
+	}
+
+	pinned := c.pinned()
+	out := make([]types.ChatCompletionMessageParam, 0, len(c.messages)+1)
+	out = append(out, c.messages[:pinned]...)
+	out = append(out, c.summaryMessage())
+	return append(out, c.messages[pinned:]...)
+}
+
+// Summary returns the summary of messages dropped by TruncateSummarize
+func (c *Conversation) Summary() string {
+	return c.summary
+}
+
+// Len returns the number of stored messages, excluding the summary
+func (c *Conversation) Len() int {
+	return len(c.messages)
+}
+
+// Reset removes all messages except the leading system messages
+func (c *Conversation) Reset() {
+	c.messages = c.messages[:c.pinned()]
+	c.summary = ""
+}
+
+// Tokens estimates the prompt tokens of Messages
+func (c *Conversation) Tokens() int {
+	return EstimateMessageTokens(c.Messages()...) + tokensPerReply
+}
+
+// Trim drops messages according to the truncation strategy until the
+// conversation and the reserved tokens fit the context window
+func (c *Conversation) Trim(ctx context.Context) error {
+	return c.trim(ctx, c.reserve)
+}
+
+// Request returns a copy of req with the trimmed conversation as its
+// messages. Tools, documents and the completion budget of req count against
+// the context window; the model defaults to the conversation's.
+func (c *Conversation) Request(ctx context.Context, req *types.CreateChatCompletionRequest) (*types.CreateChatCompletionRequest, error) {
+	out := *req
+	if out.Model == "" {
+		out.Model = c.model
+	}
+
+	out.Messages = nil
+	overhead := EstimateTokens(&out) - tokensPerReply
+	if !completionBudgetSet(&out) {
+		overhead += c.reserve
+	}
+
+	if err := c.trim(ctx, overhead); err != nil {
+		return nil, err
+	}
+	out.Messages = c.Messages()
+	return &out, nil
+}
+
+func completionBudgetSet(req *types.CreateChatCompletionRequest) bool {
+	return (req.MaxCompletionTokens != nil && req.MaxCompletionTokens.IsSet()) ||
+		(req.MaxTokens != nil && req.MaxTokens.IsSet())
+}
+
+func (c *Conversation) trim(ctx context.Context, overhead int) error {
+	budget := c.contextWindow - overhead
+	if c.strategy == TruncateSummarize && c.summarize == nil {
+		return errors.New("chat: TruncateSummarize requires WithSummarizer")
+	}
+
+	for c.Tokens() > budget {
+		start := c.pinned()
+		if c.strategy == TruncateDropOldest {
+			start = 0
+		}
+		end := c.dropEnd(start)
+		if end <= start {
+			return fmt.Errorf("%w: %d tokens, budget %d", ErrContextWindowExceeded, c.Tokens(), budget)
+		}
+
+		if c.strategy != TruncateSummarize {
+			c.messages = append(c.messages[:start], c.messages[end:]...)
+			continue
+		}
+
+		// Drop enough units to fit before summarizing, so the callback
+		// runs once per trim in the common case
+		for c.tokensWithout(start, end) > budget {
+			next := c.dropEnd(end)
+			if next <= end {
+				break
+			}
+			end = next
+		}
+		if err := c.summarizeRange(ctx, start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summarizeRange replaces messages[start:end] and the previous summary with
+// a new summary
+func (c *Conversation) summarizeRange(ctx context.Context, start, end int) error {
+	dropped := make([]types.ChatCompletionMessageParam, 0, end-start+1)
+	if c.summary != "" {
+		dropped = append(dropped, c.summaryMessage())
+	}
+	dropped = append(dropped, c.messages[start:end]...)
+
+	summary, err := c.summarize(ctx, dropped)
+	if err != nil {
+		return fmt.Errorf("chat: summarize conversation: %w", err)
+	}
+	c.messages = append(c.messages[:start], c.messages[end:]...)
+	c.summary = summary
+	return nil
+}
+
+// tokensWithout estimates Tokens with messages[start:end] removed
+func (c *Conversation) tokensWithout(start, end int) int {
+	return c.Tokens() - EstimateMessageTokens(c.messages[start:end]...)
+}
+
+// pinned returns the number of leading system messages
+func (c *Conversation) pinned() int {
+	n := 0
+	for n < len(c.messages) && c.messages[n].Role == types.RoleSystem {
+		n++
+	}
+	return n
+}
+
+// dropEnd returns the end of the unit of messages starting at start that
+// can be dropped together: a message plus the tool results that follow it.
+// The last message is never dropped.
+func (c *Conversation) dropEnd(start int) int {
+	end := start + 1
+	for end < len(c.messages) && c.messages[end].Role == types.RoleTool {
+		end++
+	}
+	if end >= len(c.messages) {
+		return start
+	}
+	return end
+}
+
+func (c *Conversation) summaryMessage() types.ChatCompletionMessageParam {
+	return types.ChatCompletionMessageParam{
+		Role:    types.RoleSystem,
+		Content: "Summary of the earlier conversation: " + c.summary,
+	}
+}
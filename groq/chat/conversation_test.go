@@ -0,0 +1,207 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// Each message below estimates to tokensPerMessage+2 = 6 tokens
+func msg(role types.Role, content string) types.ChatCompletionMessageParam {
+	return types.ChatCompletionMessageParam{Role: role, Content: content}
+}
+
+func contents(messages []types.ChatCompletionMessageParam) []string {
+	out := make([]string, len(messages))
+	for i, m := range messages {
+		out[i] = string(m.Role) + ":" + m.Content.(string)
+	}
+	return out
+}
+
+func TestConversation_Trim(t *testing.T) {
+	history := []types.ChatCompletionMessageParam{
+		msg(types.RoleUser, "user-001"),
+		msg(types.RoleAssistant, "asst-001"),
+		msg(types.RoleUser, "user-002"),
+		msg(types.RoleAssistant, "asst-002"),
+		msg(types.RoleUser, "user-003"),
+	}
+
+	tests := []struct {
+		name     string
+		strategy TruncationStrategy
+		window   int
+		want     []string
+	}{
+		{"fits", TruncateKeepSystem, 100, []string{"system:be brief", "user:user-001", "assistant:asst-001", "user:user-002", "assistant:asst-002", "user:user-003"}},
+		{"keep system", TruncateKeepSystem, 6*3 + tokensPerReply, []string{"system:be brief", "assistant:asst-002", "user:user-003"}},
+		{"drop oldest", TruncateDropOldest, 6*3 + tokensPerReply, []string{"user:user-002", "assistant:asst-002", "user:user-003"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := NewConversation("m", WithSystemPrompt("be brief"), WithContextWindow(tt.window), WithTruncation(tt.strategy))
+			conv.Append(history...)
+
+			if err := conv.Trim(context.Background()); err != nil {
+				t.Fatalf("Trim error: %v", err)
+			}
+			got := contents(conv.Messages())
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+			if conv.Tokens() > tt.window {
+				t.Errorf("tokens = %d, window %d", conv.Tokens(), tt.window)
+			}
+		})
+	}
+}
+
+func TestConversation_TrimKeepsToolResults(t *testing.T) {
+	conv := NewConversation("m", WithContextWindow(15))
+	conv.AppendUser("user-001")
+	conv.AppendCompletion(&types.ChatCompletion{Choices: []types.ChatCompletionChoice{{
+		Message: types.ChatCompletionMessage{
+			Role:      types.RoleAssistant,
+			ToolCalls: []types.ToolCall{{ID: "call_1", Type: "function", Function: types.FunctionCall{Name: "f", Arguments: "{}"}}},
+		},
+	}}})
+	conv.AppendToolResult("call_1", "result-1")
+	conv.AppendUser("user-002")
+
+	if err := conv.Trim(context.Background()); err != nil {
+		t.Fatalf("Trim error: %v", err)
+	}
+	// Dropping the assistant message must also drop its tool result
+	messages := conv.Messages()
+	if len(messages) != 1 || messages[0].Content != "user-002" {
+		t.Errorf("messages = %+v", messages)
+	}
+}
+
+func TestConversation_TrimExceeded(t *testing.T) {
+	conv := NewConversation("m", WithContextWindow(10))
+	conv.AppendUser(strings.Repeat("a", 100))
+	if err := conv.Trim(context.Background()); !errors.Is(err, ErrContextWindowExceeded) {
+		t.Errorf("error = %v, want ErrContextWindowExceeded", err)
+	}
+}
+
+func TestConversation_Summarize(t *testing.T) {
+	var calls [][]string
+	summarize := func(ctx context.Context, messages []types.ChatCompletionMessageParam) (string, error) {
+		calls = append(calls, contents(messages))
+		return "sum" + string(rune('0'+len(calls))), nil
+	}
+
+	// Messages of 24 tokens each; the summary message is 15 tokens
+	long := func(role types.Role, name string) types.ChatCompletionMessageParam {
+		return msg(role, name+strings.Repeat(".", 72))
+	}
+	conv := NewConversation("m", WithSystemPrompt("be brief"), WithContextWindow(80), WithSummarizer(summarize))
+	conv.Append(
+		long(types.RoleUser, "user-001"),
+		long(types.RoleAssistant, "asst-001"),
+		long(types.RoleUser, "user-002"),
+		long(types.RoleAssistant, "asst-002"),
+	)
+	if err := conv.Trim(context.Background()); err != nil {
+		t.Fatalf("Trim error: %v", err)
+	}
+	if len(calls) != 1 || len(calls[0]) != 2 || !strings.HasPrefix(calls[0][0], "user:user-001") || !strings.HasPrefix(calls[0][1], "assistant:asst-001") {
+		t.Fatalf("summarize calls = %q", calls)
+	}
+	if conv.Summary() != "sum1" {
+		t.Errorf("summary = %q", conv.Summary())
+	}
+
+	messages := conv.Messages()
+	if len(messages) != 4 || messages[1].Role != types.RoleSystem || !strings.Contains(messages[1].Content.(string), "sum1") {
+		t.Errorf("messages = %q", contents(messages))
+	}
+	if conv.Tokens() > 80 {
+		t.Errorf("tokens = %d", conv.Tokens())
+	}
+
+	// The previous summary is folded into the next one
+	conv.Append(long(types.RoleUser, "user-003"))
+	if err := conv.Trim(context.Background()); err != nil {
+		t.Fatalf("Trim error: %v", err)
+	}
+	if len(calls) != 2 || len(calls[1]) != 2 || !strings.Contains(calls[1][0], "sum1") || !strings.HasPrefix(calls[1][1], "user:user-002") {
+		t.Errorf("second summarize call = %q", calls[1:])
+	}
+	if conv.Summary() != "sum2" {
+		t.Errorf("summary = %q", conv.Summary())
+	}
+}
+
+func TestConversation_SummarizeError(t *testing.T) {
+	conv := NewConversation("m", WithContextWindow(20), WithSummarizer(func(ctx context.Context, messages []types.ChatCompletionMessageParam) (string, error) {
+		return "", errors.New("boom")
+	}))
+	conv.Append(msg(types.RoleUser, "user-001"), msg(types.RoleAssistant, "asst-001"), msg(types.RoleUser, "user-002"))
+
+	if err := conv.Trim(context.Background()); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("error = %v", err)
+	}
+	if conv.Len() != 3 {
+		t.Errorf("messages dropped despite summarize error: %d left", conv.Len())
+	}
+}
+
+func TestConversation_AppendCompletion(t *testing.T) {
+	reasoning := "thinking"
+	index := 0
+	conv := NewConversation("m")
+	conv.AppendCompletion(&types.ChatCompletion{Choices: []types.ChatCompletionChoice{{
+		Message: types.ChatCompletionMessage{
+			Role:      types.RoleAssistant,
+			Reasoning: &reasoning,
+			ToolCalls: []types.ToolCall{{Index: &index, ID: "call_1", Type: "function", Function: types.FunctionCall{Name: "f"}}},
+		},
+	}}})
+
+	got := conv.Messages()[0]
+	if got.Role != types.RoleAssistant || got.Content != nil || got.Reasoning == nil || *got.Reasoning != "thinking" {
+		t.Errorf("message = %+v", got)
+	}
+	if len(got.ToolCalls) != 1 || got.ToolCalls[0].ID != "call_1" || got.ToolCalls[0].Index != nil {
+		t.Errorf("tool calls = %+v", got.ToolCalls)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestConversation_Request(t *testing.T) {
+	conv := NewConversation(string(types.ModelGemma29BIT), WithSystemPrompt("be brief"))
+	if conv.contextWindow != 8192 {
+		t.Errorf("context window = %d, want 8192", conv.contextWindow)
+	}
+	conv.Append(msg(types.RoleUser, "user-001"), msg(types.RoleAssistant, "asst-001"), msg(types.RoleUser, "user-002"))
+
+	// The completion budget leaves room for the system prompt and the last message only
+	base := &types.CreateChatCompletionRequest{MaxCompletionTokens: option.Ptr(option.Some(8192 - 6*2 - tokensPerReply))}
+	req, err := conv.Request(context.Background(), base)
+	if err != nil {
+		t.Fatalf("Request error: %v", err)
+	}
+	if req.Model != string(types.ModelGemma29BIT) {
+		t.Errorf("model = %q", req.Model)
+	}
+	if got := contents(req.Messages); strings.Join(got, "|") != "system:be brief|user:user-002" {
+		t.Errorf("messages = %q", got)
+	}
+	if base.Messages != nil || base.Model != "" {
+		t.Error("caller's request was modified")
+	}
+	if EstimateTokens(req) > 8192 {
+		t.Errorf("estimated tokens = %d", EstimateTokens(req))
+	}
+}
@@ -0,0 +1,229 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+
+	"github.com/ZaguanLabs/groq-go/groq/jsonschema"
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// ParsedCompletion is a chat completion whose content was decoded into T
+type ParsedCompletion[T any] struct {
+	*types.ChatCompletion
+	Parsed T
+}
+
+// RefusalError is returned when the model refused to answer
+type RefusalError struct {
+	Refusal    string
+	Completion *types.ChatCompletion
+}
+
+func (e *RefusalError) Error() string {
+	return "chat: model refused: " + e.Refusal
+}
+
+// LengthFinishReasonError is returned when the output was cut off by the
+// token limit before the JSON was complete
+type LengthFinishReasonError struct {
+	Completion *types.ChatCompletion
+}
+
+func (e *LengthFinishReasonError) Error() string {
+	return "chat: output truncated by the token limit (finish_reason=length)"
+}
+
+// ParseError is returned when the content is not valid JSON, does not fit
+// T, or violates the schema
+type ParseError struct {
+	Content    string
+	Completion *types.ChatCompletion
+	Err        error // *json.SyntaxError, *json.UnmarshalTypeError or *jsonschema.ValidationError
+}
+
+func (e *ParseError) Error() string {
+	return "chat: parse structured output: " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse requests structured output matching T and decodes it. If
+// req.ResponseFormat is nil, a strict json_schema format is generated from T
+// (see the jsonschema package); req itself is not modified. The content is
+// validated against the json_schema sent with the request.
+func Parse[T any](ctx context.Context, c *Completions, req *types.CreateChatCompletionRequest, opts ...option.RequestOption) (*ParsedCompletion[T], error) {
+	parseReq, schema, err := structuredRequest[T](req)
+	if err != nil {
+		return nil, err
+	}
+
+	completion, err := c.Create(ctx, parseReq, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCompletion[T](completion, schema)
+}
+
+// structuredRequest returns a copy of req with a response format for T,
+// and the schema its output must satisfy, if known
+func structuredRequest[T any](req *types.CreateChatCompletionRequest) (*types.CreateChatCompletionRequest, jsonschema.Schema, error) {
+	out := *req
+	if out.ResponseFormat == nil {
+		schema, err := jsonschema.For[T]()
+		if err != nil {
+			return nil, nil, err
+		}
+		strict := true
+		out.ResponseFormat = &types.ResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &types.ResponseFormatJSONSchema{
+				Name:   schemaName[T](),
+				Schema: schema,
+				Strict: &strict,
+			},
+		}
+		return &out, schema, nil
+	}
+
+	if f := out.ResponseFormat; f.Type == "json_schema" && f.JSONSchema != nil {
+		return &out, f.JSONSchema.Schema, nil
+	}
+	return &out, nil, nil
+}
+
+var invalidSchemaName = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// schemaName derives a valid json_schema name from T's type name
+func schemaName[T any]() string {
+	name := invalidSchemaName.ReplaceAllString(reflect.TypeOf((*T)(nil)).Elem().Name(), "_")
+	if name == "" || name == "_" {
+		return "response"
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func parseCompletion[T any](completion *types.ChatCompletion, schema jsonschema.Schema) (*ParsedCompletion[T], error) {
+	result := &ParsedCompletion[T]{ChatCompletion: completion}
+	if len(completion.Choices) == 0 {
+		return result, &ParseError{Completion: completion, Err: errors.New("no choices in response")}
+	}
+
+	choice := completion.Choices[0]
+	if choice.Message.Refusal != "" {
+		return result, &RefusalError{Refusal: choice.Message.Refusal, Completion: completion}
+	}
+	if choice.FinishReason == types.FinishReasonLength {
+		return result, &LengthFinishReasonError{Completion: completion}
+	}
+
+	content := choice.Message.Content
+	if schema != nil {
+		if err := jsonschema.ValidateJSON(schema, []byte(content)); err != nil {
+			return result, &ParseError{Content: content, Completion: completion, Err: err}
+		}
+	}
+	if err := json.Unmarshal([]byte(content), &result.Parsed); err != nil {
+		return result, &ParseError{Content: content, Completion: completion, Err: err}
+	}
+	return result, nil
+}
+
+// PartialStream yields partially decoded structured output as a response
+// streams in
+type PartialStream[T any] struct {
+	stream *Stream[types.ChatCompletionChunk]
+	schema jsonschema.Schema
+	acc    ChatCompletionAccumulator
+
+	parsed string // Completed JSON last decoded
+}
+
+// ParseStream is the streaming variant of Parse. Each call to Next returns
+// the object decoded from the JSON received so far, with unfinished strings,
+// arrays and objects closed.
+//
+//	stream, err := chat.ParseStream[Recipe](ctx, client.Chat, req)
+//	defer stream.Close()
+//	for {
+//		partial, err := stream.Next(ctx)
+//		if err == io.EOF {
+//			break
+//		}
+//		// render partial
+//	}
+//	recipe, err := stream.Final(ctx)
+func ParseStream[T any](ctx context.Context, c *Completions, req *types.CreateChatCompletionRequest, opts ...option.RequestOption) (*PartialStream[T], error) {
+	parseReq, schema, err := structuredRequest[T](req)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.CreateStream(ctx, parseReq, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &PartialStream[T]{stream: stream, schema: schema}, nil
+}
+
+// Next reads until the decoded object changes and returns it. It returns
+// io.EOF when the stream is complete; call Final for the validated result.
+func (s *PartialStream[T]) Next(ctx context.Context) (*T, error) {
+	for {
+		chunk, err := s.stream.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.acc.AddChunk(chunk)
+
+		completion := s.acc.ChatCompletion()
+		if len(completion.Choices) == 0 {
+			continue
+		}
+		completed, ok := completePartialJSON(completion.Choices[0].Message.Content)
+		if !ok || completed == s.parsed {
+			continue
+		}
+
+		var partial T
+		if json.Unmarshal([]byte(completed), &partial) != nil {
+			continue
+		}
+		s.parsed = completed
+		return &partial, nil
+	}
+}
+
+// Final consumes the rest of the stream and returns the complete result,
+// checked like Parse
+func (s *PartialStream[T]) Final(ctx context.Context) (*ParsedCompletion[T], error) {
+	for {
+		chunk, err := s.stream.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.acc.AddChunk(chunk)
+	}
+	if err := s.acc.Err(); err != nil {
+		return nil, err
+	}
+	return parseCompletion[T](s.acc.ChatCompletion(), s.schema)
+}
+
+// Close closes the underlying stream
+func (s *PartialStream[T]) Close() error {
+	return s.stream.Close()
+}
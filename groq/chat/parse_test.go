@@ -0,0 +1,215 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/jsonschema"
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+type recipe struct {
+	Name        string   `json:"name"`
+	Difficulty  string   `json:"difficulty" enum:"easy,hard"`
+	Ingredients []string `json:"ingredients"`
+}
+
+func completionWith(content string, finish types.FinishReason, refusal string) *types.ChatCompletion {
+	return &types.ChatCompletion{
+		ID: "chatcmpl-parse",
+		Choices: []types.ChatCompletionChoice{{
+			FinishReason: finish,
+			Message:      types.ChatCompletionMessage{Role: types.RoleAssistant, Content: content, Refusal: refusal},
+		}},
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		completion *types.ChatCompletion
+		wantErr    interface{}
+		want       recipe
+	}{
+		{
+			name:       "valid",
+			completion: completionWith(`{"name":"Soup","difficulty":"easy","ingredients":["water","salt"]}`, types.FinishReasonStop, ""),
+			want:       recipe{Name: "Soup", Difficulty: "easy", Ingredients: []string{"water", "salt"}},
+		},
+		{
+			name:       "refusal",
+			completion: completionWith("", types.FinishReasonStop, "I can't help with that"),
+			wantErr:    new(*RefusalError),
+		},
+		{
+			name:       "truncated",
+			completion: completionWith(`{"name":"Sou`, types.FinishReasonLength, ""),
+			wantErr:    new(*LengthFinishReasonError),
+		},
+		{
+			name:       "invalid JSON",
+			completion: completionWith(`{"name":`, types.FinishReasonStop, ""),
+			wantErr:    new(*json.SyntaxError),
+		},
+		{
+			name:       "schema violation",
+			completion: completionWith(`{"name":"Soup","difficulty":"medium","ingredients":[]}`, types.FinishReasonStop, ""),
+			wantErr:    new(*jsonschema.ValidationError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent *types.CreateChatCompletionRequest
+			mock := &mockRequester{
+				postFunc: func(ctx context.Context, path string, body, result interface{}, opts ...option.RequestOption) error {
+					sent = body.(*types.CreateChatCompletionRequest)
+					*result.(*types.ChatCompletion) = *tt.completion
+					return nil
+				},
+			}
+
+			req := &types.CreateChatCompletionRequest{Model: "m"}
+			got, err := Parse[recipe](context.Background(), NewCompletions(mock), req)
+
+			if req.ResponseFormat != nil {
+				t.Error("caller's request was modified")
+			}
+			if f := sent.ResponseFormat; f == nil || f.Type != "json_schema" || f.JSONSchema.Name != "recipe" || !*f.JSONSchema.Strict {
+				t.Errorf("response format = %+v", sent.ResponseFormat)
+			}
+
+			if tt.wantErr != nil {
+				if err == nil || !errors.As(err, tt.wantErr) {
+					t.Fatalf("error = %v (%T), want %T", err, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if got.Parsed.Name != tt.want.Name || got.Parsed.Difficulty != tt.want.Difficulty || len(got.Parsed.Ingredients) != 2 {
+				t.Errorf("parsed = %+v, want %+v", got.Parsed, tt.want)
+			}
+			if got.ID != "chatcmpl-parse" {
+				t.Errorf("completion not embedded: %+v", got.ChatCompletion)
+			}
+		})
+	}
+}
+
+func TestParse_CallerResponseFormat(t *testing.T) {
+	mock := &mockRequester{
+		postFunc: func(ctx context.Context, path string, body, result interface{}, opts ...option.RequestOption) error {
+			if f := body.(*types.CreateChatCompletionRequest).ResponseFormat; f.Type != "json_object" {
+				t.Errorf("response format = %+v, want caller's", f)
+			}
+			*result.(*types.ChatCompletion) = *completionWith(`{"name":"Tea","extra":true}`, types.FinishReasonStop, "")
+			return nil
+		},
+	}
+
+	req := &types.CreateChatCompletionRequest{Model: "m", ResponseFormat: &types.ResponseFormat{Type: "json_object"}}
+	got, err := Parse[recipe](context.Background(), NewCompletions(mock), req)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if got.Parsed.Name != "Tea" {
+		t.Errorf("parsed = %+v", got.Parsed)
+	}
+}
+
+func TestParseStream(t *testing.T) {
+	parts := []string{`{"name":"So`, `up","ingre`, `dients":["wa`, `ter"`, `],"difficulty":"easy"}`}
+	var sb strings.Builder
+	for _, p := range parts {
+		chunk, _ := json.Marshal(types.ChatCompletionChunk{
+			ID:      "c",
+			Choices: []types.ChatCompletionChunkChoice{{Delta: types.ChatCompletionChunkDelta{Content: p}}},
+		})
+		sb.WriteString("data: " + string(chunk) + "\n\n")
+	}
+	sb.WriteString(`data: {"id":"c","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}` + "\n\ndata: [DONE]\n\n")
+
+	mock := &mockRequester{
+		postStreamFunc: func(ctx context.Context, path string, body interface{}, opts ...option.RequestOption) (*http.Response, error) {
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(sb.String())), Header: make(http.Header)}, nil
+		},
+	}
+
+	stream, err := ParseStream[recipe](context.Background(), NewCompletions(mock), &types.CreateChatCompletionRequest{Model: "m"})
+	if err != nil {
+		t.Fatalf("ParseStream error: %v", err)
+	}
+	defer stream.Close()
+
+	var names []string
+	var lastIngredients []string
+	for {
+		partial, err := stream.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next error: %v", err)
+		}
+		names = append(names, partial.Name)
+		lastIngredients = partial.Ingredients
+	}
+
+	if len(names) < 3 || names[0] != "So" || names[len(names)-1] != "Soup" {
+		t.Errorf("partial names = %q", names)
+	}
+	if len(lastIngredients) != 1 || lastIngredients[0] != "water" {
+		t.Errorf("ingredients = %q", lastIngredients)
+	}
+
+	final, err := stream.Final(context.Background())
+	if err != nil {
+		t.Fatalf("Final error: %v", err)
+	}
+	if final.Parsed.Difficulty != "easy" {
+		t.Errorf("final = %+v", final.Parsed)
+	}
+}
+
+func TestCompletePartialJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{``, ``, false},
+		{`{`, `{}`, true},
+		{`{"na`, `{}`, true},
+		{`{"name"`, `{}`, true},
+		{`{"name":`, `{}`, true},
+		{`{"name":"Sou`, `{"name":"Sou"}`, true},
+		{`{"name":"a\`, `{"name":"a"}`, true},
+		{`{"name":"a\u00`, `{"name":"a"}`, true},
+		{`{"name":"a\"b`, `{"name":"a\"b"}`, true},
+		{`{"a":1,"b":[1,2`, `{"a":1,"b":[1,2]}`, true},
+		{`{"a":1,"b":[1,-`, `{"a":1,"b":[1]}`, true},
+		{`{"a":tr`, `{}`, true},
+		{`{"a":true,`, `{"a":true}`, true},
+		{`[{"a":{"b":null}},{"c":"d"`, `[{"a":{"b":null}},{"c":"d"}]`, true},
+		{`{"a":1}`, `{"a":1}`, true},
+		{`{"a":1}}`, ``, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := completePartialJSON(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("completePartialJSON(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+		if ok && !json.Valid([]byte(got)) {
+			t.Errorf("completePartialJSON(%q) = %q is not valid JSON", tt.in, got)
+		}
+	}
+}
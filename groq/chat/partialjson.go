@@ -0,0 +1,137 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// completePartialJSON closes the strings, arrays and objects left open in a
+// truncated JSON document. Incomplete keys and literals are dropped. It
+// reports false if nothing complete has been received yet or the input is
+// malformed.
+func completePartialJSON(s string) (string, bool) {
+	var stack []byte // Open '{' and '['
+	expectKey := false
+
+	// The last position where the document can be cut and closed
+	safe := -1
+	var safeStack []byte
+	mark := func(i int) {
+		safe = i
+		safeStack = append(safeStack[:0], stack...)
+	}
+
+	i := 0
+	for i < len(s) {
+		switch c := s[i]; c {
+		case ' ', '\t', '\n', '\r':
+			i++
+		case '{', '[':
+			stack = append(stack, c)
+			expectKey = c == '{'
+			i++
+			mark(i)
+		case '}', ']':
+			if len(stack) == 0 {
+				return "", false
+			}
+			stack = stack[:len(stack)-1]
+			expectKey = false
+			i++
+			mark(i)
+		case ',':
+			expectKey = len(stack) > 0 && stack[len(stack)-1] == '{'
+			i++
+		case ':':
+			expectKey = false
+			i++
+		case '"':
+			isKey := expectKey
+			end := stringEnd(s, i)
+			if end < 0 {
+				if isKey {
+					return closeJSON(s, safe, safeStack)
+				}
+				return trimPartialEscape(s) + `"` + closers(stack), true
+			}
+			i = end + 1
+			if !isKey {
+				mark(i)
+			}
+		default:
+			j := i
+			for j < len(s) && !isDelimiter(s[j]) {
+				j++
+			}
+			valid := json.Valid([]byte(s[i:j]))
+			if !valid && j < len(s) {
+				return "", false
+			}
+			if valid {
+				mark(j)
+			}
+			i = j
+		}
+	}
+	return closeJSON(s, safe, safeStack)
+}
+
+func closeJSON(s string, safe int, stack []byte) (string, bool) {
+	if safe < 0 {
+		return "", false
+	}
+	return s[:safe] + closers(stack), true
+}
+
+// stringEnd returns the index of the quote closing the string starting at
+// start, or -1 if it is unterminated
+func stringEnd(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// trimPartialEscape drops an escape sequence cut off at the end of s
+func trimPartialEscape(s string) string {
+	if i := strings.LastIndexByte(s, '\\'); i >= 0 {
+		// Count the run of backslashes ending at i
+		n := 0
+		for j := i; j >= 0 && s[j] == '\\'; j-- {
+			n++
+		}
+		tail := s[i+1:]
+		switch {
+		case n%2 == 1 && tail == "":
+			return s[:i]
+		case n%2 == 1 && strings.HasPrefix(tail, "u") && len(tail) < 5:
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func closers(stack []byte) string {
+	b := make([]byte, len(stack))
+	for i, c := range stack {
+		if c == '{' {
+			b[len(stack)-1-i] = '}'
+		} else {
+			b[len(stack)-1-i] = ']'
+		}
+	}
+	return string(b)
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case ',', ']', '}', ':', ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
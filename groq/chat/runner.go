@@ -0,0 +1,375 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// DefaultMaxIterations is the default number of model calls a Runner makes
+// before giving up
+const DefaultMaxIterations = 10
+
+// ErrMaxIterations is returned when the model still requests tools after the
+// maximum number of iterations
+var ErrMaxIterations = errors.New("chat: runner reached maximum iterations")
+
+// ToolHandler executes a tool call. It receives the raw JSON arguments and
+// returns the content of the tool message sent back to the model.
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// RunnerEventType identifies a step of a Runner
+type RunnerEventType string
+
+const (
+	RunnerEventChunk      RunnerEventType = "chunk"       // A streamed chunk was received
+	RunnerEventCompletion RunnerEventType = "completion"  // The model responded
+	RunnerEventToolCall   RunnerEventType = "tool_call"   // A tool is about to run
+	RunnerEventToolResult RunnerEventType = "tool_result" // A tool finished
+)
+
+// RunnerEvent reports a step of a Runner. Only the fields relevant to Type
+// are set.
+type RunnerEvent struct {
+	Type       RunnerEventType
+	Iteration  int // Zero-based model call
+	Chunk      *types.ChatCompletionChunk
+	Completion *types.ChatCompletion
+	ToolCall   *types.ToolCall
+	Result     string // Tool message content
+	Err        error  // Tool error, already converted into Result
+}
+
+// RunResult is the outcome of a Runner
+type RunResult struct {
+	// Completion is the last model response
+	Completion *types.ChatCompletion
+	// Messages is the full conversation, including the request messages,
+	// assistant tool calls and tool results
+	Messages []types.ChatCompletionMessageParam
+	// Iterations is the number of model calls made
+	Iterations int
+	// Usage is summed over all model calls
+	Usage types.CompletionUsage
+}
+
+// Runner automates function calling: it calls the model, runs the Go
+// handlers for the tools it requests, sends back the results and repeats
+// until the model stops asking for tools.
+type Runner struct {
+	completions   *Completions
+	tools         []runnerTool
+	maxIterations int
+	toolTimeout   time.Duration
+	onEvent       func(RunnerEvent)
+	formatError   func(call types.ToolCall, err error) string
+
+	eventMu sync.Mutex
+}
+
+type runnerTool struct {
+	definition types.ChatCompletionTool
+	handler    ToolHandler
+	timeout    time.Duration // Overrides Runner.toolTimeout if set
+}
+
+// RunnerOption configures a Runner
+type RunnerOption func(*Runner)
+
+// ToolOption configures a registered tool
+type ToolOption func(*runnerTool)
+
+// WithHandlerTimeout bounds calls of one tool, overriding WithToolTimeout
+func WithHandlerTimeout(d time.Duration) ToolOption {
+	return func(t *runnerTool) { t.timeout = d }
+}
+
+// WithTool registers a tool definition and its handler
+func WithTool(tool types.ChatCompletionTool, handler ToolHandler, opts ...ToolOption) RunnerOption {
+	return func(r *Runner) { r.Register(tool, handler, opts...) }
+}
+
+// WithMaxIterations sets the maximum number of model calls
+func WithMaxIterations(n int) RunnerOption {
+	return func(r *Runner) { r.maxIterations = n }
+}
+
+// WithToolTimeout bounds each tool handler call. Zero means no timeout. A
+// call that times out is reported to the model as an error; a handler that
+// ignores its context keeps running in the background until it returns.
+func WithToolTimeout(d time.Duration) RunnerOption {
+	return func(r *Runner) { r.toolTimeout = d }
+}
+
+// WithRunnerEvents sets a callback for each step of the loop. Calls are
+// serialized, even when tools run in parallel.
+func WithRunnerEvents(fn func(RunnerEvent)) RunnerOption {
+	return func(r *Runner) { r.onEvent = fn }
+}
+
+// WithToolErrorFormatter sets how a failed tool call is reported to the
+// model. By default the message is "Error: " followed by the error.
+func WithToolErrorFormatter(fn func(call types.ToolCall, err error) string) RunnerOption {
+	return func(r *Runner) { r.formatError = fn }
+}
+
+// NewRunner creates a Runner that sends requests through completions
+func NewRunner(completions *Completions, opts ...RunnerOption) *Runner {
+	r := &Runner{
+		completions:   completions,
+		maxIterations: DefaultMaxIterations,
+		formatError: func(_ types.ToolCall, err error) string {
+			return "Error: " + err.Error()
+		},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Register adds a tool, replacing any tool with the same function name
+func (r *Runner) Register(tool types.ChatCompletionTool, handler ToolHandler, opts ...ToolOption) {
+	if tool.Type == "" {
+		tool.Type = "function"
+	}
+	t := runnerTool{definition: tool, handler: handler}
+	for _, opt := range opts {
+		opt(&t)
+	}
+	for i := range r.tools {
+		if r.tools[i].definition.Function.Name == tool.Function.Name {
+			r.tools[i] = t
+			return
+		}
+	}
+	r.tools = append(r.tools, t)
+}
+
+// Run executes the tool loop with non-streaming requests. The registered
+// tools are added to req.Tools; req itself is not modified.
+func (r *Runner) Run(ctx context.Context, req *types.CreateChatCompletionRequest, opts ...option.RequestOption) (*RunResult, error) {
+	return r.run(ctx, req, func(ctx context.Context, req *types.CreateChatCompletionRequest, _ int) (*types.ChatCompletion, error) {
+		return r.completions.Create(ctx, req, opts...)
+	})
+}
+
+// RunStream executes the tool loop with streaming requests, reporting every
+// chunk as a RunnerEventChunk event
+func (r *Runner) RunStream(ctx context.Context, req *types.CreateChatCompletionRequest, opts ...option.RequestOption) (*RunResult, error) {
+	return r.run(ctx, req, func(ctx context.Context, req *types.CreateChatCompletionRequest, iteration int) (*types.ChatCompletion, error) {
+		stream, err := r.completions.CreateStream(ctx, req, opts...)
+		if err != nil {
+			return nil, err
+		}
+		defer stream.Close()
+
+		var acc ChatCompletionAccumulator
+		for {
+			chunk, err := stream.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				return acc.ChatCompletion(), acc.Err()
+			}
+			if err != nil {
+				return nil, err
+			}
+			acc.AddChunk(chunk)
+			r.emit(RunnerEvent{Type: RunnerEventChunk, Iteration: iteration, Chunk: chunk})
+		}
+	})
+}
+
+type completeFunc func(ctx context.Context, req *types.CreateChatCompletionRequest, iteration int) (*types.ChatCompletion, error)
+
+func (r *Runner) run(ctx context.Context, req *types.CreateChatCompletionRequest, complete completeFunc) (*RunResult, error) {
+	base := *req
+	base.Tools = r.mergeTools(req.Tools)
+	parallel := req.ParallelToolCalls != nil && req.ParallelToolCalls.IsSet() && req.ParallelToolCalls.Value
+
+	result := &RunResult{
+		Messages: append([]types.ChatCompletionMessageParam(nil), req.Messages...),
+	}
+
+	for iteration := 0; iteration < r.maxIterations; iteration++ {
+		iterReq := base
+		iterReq.Messages = result.Messages
+
+		completion, err := complete(ctx, &iterReq, iteration)
+		if err != nil {
+			return result, err
+		}
+		result.Completion = completion
+		result.Iterations++
+		addUsage(&result.Usage, completion.Usage)
+		r.emit(RunnerEvent{Type: RunnerEventCompletion, Iteration: iteration, Completion: completion})
+
+		if len(completion.Choices) == 0 {
+			return result, nil
+		}
+		msg := completion.Choices[0].Message
+		if len(msg.ToolCalls) == 0 {
+			return result, nil
+		}
+
+		result.Messages = append(result.Messages, types.ChatCompletionMessageParam{
+			Role:      types.RoleAssistant,
+			Content:   msg.Content,
+			ToolCalls: msg.ToolCalls,
+		})
+
+		contents := r.runTools(ctx, iteration, msg.ToolCalls, parallel)
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		for i, call := range msg.ToolCalls {
+			result.Messages = append(result.Messages, types.ChatCompletionMessageParam{
+				Role:       types.RoleTool,
+				Content:    contents[i],
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	return result, ErrMaxIterations
+}
+
+// mergeTools adds the registered tools to those already in the request
+func (r *Runner) mergeTools(existing []types.ChatCompletionTool) []types.ChatCompletionTool {
+	tools := append([]types.ChatCompletionTool(nil), existing...)
+	for _, t := range r.tools {
+		found := false
+		for _, e := range existing {
+			if e.Function.Name == t.definition.Function.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tools = append(tools, t.definition)
+		}
+	}
+	return tools
+}
+
+// runTools executes the tool calls and returns the tool message contents in
+// call order
+func (r *Runner) runTools(ctx context.Context, iteration int, calls []types.ToolCall, parallel bool) []string {
+	contents := make([]string, len(calls))
+	if !parallel || len(calls) == 1 {
+		for i := range calls {
+			contents[i] = r.runTool(ctx, iteration, &calls[i])
+		}
+		return contents
+	}
+
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			contents[i] = r.runTool(ctx, iteration, &calls[i])
+		}(i)
+	}
+	wg.Wait()
+	return contents
+}
+
+func (r *Runner) runTool(ctx context.Context, iteration int, call *types.ToolCall) string {
+	r.emit(RunnerEvent{Type: RunnerEventToolCall, Iteration: iteration, ToolCall: call})
+
+	content, err := r.callHandler(ctx, call)
+	if err != nil {
+		content = r.formatError(*call, err)
+	}
+
+	r.emit(RunnerEvent{Type: RunnerEventToolResult, Iteration: iteration, ToolCall: call, Result: content, Err: err})
+	return content
+}
+
+func (r *Runner) callHandler(ctx context.Context, call *types.ToolCall) (string, error) {
+	var tool *runnerTool
+	for i := range r.tools {
+		if r.tools[i].definition.Function.Name == call.Function.Name {
+			tool = &r.tools[i]
+			break
+		}
+	}
+	if tool == nil {
+		return "", fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+
+	timeout := r.toolTimeout
+	if tool.timeout > 0 {
+		timeout = tool.timeout
+	}
+	callCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Run the handler in its own goroutine so a handler that ignores ctx
+	// cannot block the loop past the timeout
+	type handlerResult struct {
+		content string
+		err     error
+	}
+	done := make(chan handlerResult, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- handlerResult{err: fmt.Errorf("tool %q panicked: %v", call.Function.Name, p)}
+			}
+		}()
+		content, err := tool.handler(callCtx, call.Function.Arguments)
+		done <- handlerResult{content, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err == nil || callCtx.Err() == nil {
+			return res.content, res.err
+		}
+	case <-callCtx.Done():
+		select {
+		case res := <-done:
+			if res.err == nil {
+				return res.content, nil
+			}
+		default:
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("tool %q timed out after %s: %w", call.Function.Name, timeout, context.DeadlineExceeded)
+}
+
+func (r *Runner) emit(event RunnerEvent) {
+	if r.onEvent == nil {
+		return
+	}
+	r.eventMu.Lock()
+	defer r.eventMu.Unlock()
+	r.onEvent(event)
+}
+
+func addUsage(total *types.CompletionUsage, u *types.CompletionUsage) {
+	if u == nil {
+		return
+	}
+	total.PromptTokens += u.PromptTokens
+	total.CompletionTokens += u.CompletionTokens
+	total.TotalTokens += u.TotalTokens
+	total.PromptTime += u.PromptTime
+	total.CompletionTime += u.CompletionTime
+	total.TotalTime += u.TotalTime
+	total.QueueTime += u.QueueTime
+}
@@ -0,0 +1,376 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+var weatherTool = types.ChatCompletionTool{
+	Type: "function",
+	Function: types.FunctionDefinition{
+		Name:        "get_weather",
+		Description: "Get the weather for a city",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+		},
+	},
+}
+
+func toolCallCompletion(calls ...types.ToolCall) *types.ChatCompletion {
+	return &types.ChatCompletion{
+		ID: "chatcmpl-tools",
+		Choices: []types.ChatCompletionChoice{{
+			FinishReason: types.FinishReasonToolCalls,
+			Message:      types.ChatCompletionMessage{Role: types.RoleAssistant, ToolCalls: calls},
+		}},
+		Usage: &types.CompletionUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}
+}
+
+func textCompletion(content string) *types.ChatCompletion {
+	return &types.ChatCompletion{
+		ID: "chatcmpl-text",
+		Choices: []types.ChatCompletionChoice{{
+			FinishReason: types.FinishReasonStop,
+			Message:      types.ChatCompletionMessage{Role: types.RoleAssistant, Content: content},
+		}},
+		Usage: &types.CompletionUsage{PromptTokens: 20, CompletionTokens: 3, TotalTokens: 23},
+	}
+}
+
+func call(id, name, args string) types.ToolCall {
+	return types.ToolCall{ID: id, Type: "function", Function: types.FunctionCall{Name: name, Arguments: args}}
+}
+
+// scriptedRequester returns the given completions in order and records the
+// requests it receives
+func scriptedRequester(t *testing.T, responses ...*types.ChatCompletion) (*mockRequester, *[]types.CreateChatCompletionRequest) {
+	var requests []types.CreateChatCompletionRequest
+	mock := &mockRequester{
+		postFunc: func(ctx context.Context, path string, body, result interface{}, opts ...option.RequestOption) error {
+			req := body.(*types.CreateChatCompletionRequest)
+			requests = append(requests, *req)
+			if len(requests) > len(responses) {
+				t.Fatalf("unexpected request %d", len(requests))
+			}
+			*result.(*types.ChatCompletion) = *responses[len(requests)-1]
+			return nil
+		},
+	}
+	return mock, &requests
+}
+
+func TestRunner_Run(t *testing.T) {
+	mock, requests := scriptedRequester(t,
+		toolCallCompletion(call("call_1", "get_weather", `{"city":"Paris"}`)),
+		textCompletion("It is sunny in Paris."),
+	)
+
+	var events []RunnerEventType
+	runner := NewRunner(NewCompletions(mock),
+		WithTool(weatherTool, func(ctx context.Context, arguments string) (string, error) {
+			var args struct{ City string }
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", err
+			}
+			return "sunny in " + args.City, nil
+		}),
+		WithRunnerEvents(func(e RunnerEvent) { events = append(events, e.Type) }),
+	)
+
+	req := &types.CreateChatCompletionRequest{
+		Model:    "llama-3.3-70b-versatile",
+		Messages: []types.ChatCompletionMessageParam{{Role: types.RoleUser, Content: "Weather in Paris?"}},
+	}
+	result, err := runner.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if result.Completion.Choices[0].Message.Content != "It is sunny in Paris." {
+		t.Errorf("final content = %q", result.Completion.Choices[0].Message.Content)
+	}
+	if result.Iterations != 2 {
+		t.Errorf("iterations = %d, want 2", result.Iterations)
+	}
+	if result.Usage.TotalTokens != 38 {
+		t.Errorf("total tokens = %d, want 38", result.Usage.TotalTokens)
+	}
+
+	if len(req.Messages) != 1 || req.Tools != nil {
+		t.Error("caller's request was modified")
+	}
+	if got := (*requests)[0].Tools; len(got) != 1 || got[0].Function.Name != "get_weather" {
+		t.Errorf("tools sent = %+v", got)
+	}
+
+	second := (*requests)[1].Messages
+	if len(second) != 3 {
+		t.Fatalf("second request has %d messages, want 3", len(second))
+	}
+	if second[1].Role != types.RoleAssistant || len(second[1].ToolCalls) != 1 {
+		t.Errorf("assistant message = %+v", second[1])
+	}
+	if second[2].Role != types.RoleTool || second[2].ToolCallID != "call_1" || second[2].Content != "sunny in Paris" {
+		t.Errorf("tool message = %+v", second[2])
+	}
+	if len(result.Messages) != 3 {
+		t.Errorf("result has %d messages, want 3", len(result.Messages))
+	}
+
+	want := []RunnerEventType{RunnerEventCompletion, RunnerEventToolCall, RunnerEventToolResult, RunnerEventCompletion}
+	if strings.Join(eventNames(events), ",") != strings.Join(eventNames(want), ",") {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func eventNames(events []RunnerEventType) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = string(e)
+	}
+	return out
+}
+
+func TestRunner_ToolErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    types.ToolCall
+		handler ToolHandler
+		timeout time.Duration
+		want    string
+	}{
+		{
+			name: "handler error",
+			call: call("c1", "get_weather", `{}`),
+			handler: func(ctx context.Context, arguments string) (string, error) {
+				return "", errors.New("city is required")
+			},
+			want: "Error: city is required",
+		},
+		{
+			name: "unknown tool",
+			call: call("c1", "delete_everything", `{}`),
+			want: `Error: unknown tool "delete_everything"`,
+		},
+		{
+			name: "panic",
+			call: call("c1", "get_weather", `{}`),
+			handler: func(ctx context.Context, arguments string) (string, error) {
+				panic("boom")
+			},
+			want: `Error: tool "get_weather" panicked: boom`,
+		},
+		{
+			name: "timeout",
+			call: call("c1", "get_weather", `{}`),
+			handler: func(ctx context.Context, arguments string) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+			timeout: 10 * time.Millisecond,
+			want:    `Error: tool "get_weather" timed out after 10ms: context deadline exceeded`,
+		},
+		{
+			name: "timeout ignored by handler",
+			call: call("c1", "get_weather", `{}`),
+			handler: func(ctx context.Context, arguments string) (string, error) {
+				time.Sleep(time.Second)
+				return "sunny", nil
+			},
+			timeout: 10 * time.Millisecond,
+			want:    `Error: tool "get_weather" timed out after 10ms: context deadline exceeded`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, requests := scriptedRequester(t, toolCallCompletion(tt.call), textCompletion("done"))
+
+			opts := []RunnerOption{WithToolTimeout(tt.timeout)}
+			if tt.handler != nil {
+				opts = append(opts, WithTool(weatherTool, tt.handler))
+			}
+			runner := NewRunner(NewCompletions(mock), opts...)
+
+			_, err := runner.Run(context.Background(), &types.CreateChatCompletionRequest{Model: "m"})
+			if err != nil {
+				t.Fatalf("Run error: %v", err)
+			}
+
+			msgs := (*requests)[1].Messages
+			if got := msgs[len(msgs)-1].Content; got != tt.want {
+				t.Errorf("tool message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_HandlerTimeout(t *testing.T) {
+	blocking := func(ctx context.Context, arguments string) (string, error) {
+		time.Sleep(time.Second)
+		return "late", nil
+	}
+	quick := types.ChatCompletionTool{Type: "function", Function: types.FunctionDefinition{Name: "quick"}}
+
+	mock, requests := scriptedRequester(t,
+		toolCallCompletion(call("c1", "get_weather", `{}`), call("c2", "quick", `{}`)),
+		textCompletion("done"))
+	runner := NewRunner(NewCompletions(mock),
+		WithToolTimeout(time.Minute),
+		WithTool(weatherTool, blocking, WithHandlerTimeout(10*time.Millisecond)),
+		WithTool(quick, func(ctx context.Context, arguments string) (string, error) { return "ok", nil }),
+	)
+
+	start := time.Now()
+	if _, err := runner.Run(context.Background(), &types.CreateChatCompletionRequest{Model: "m"}); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run took %s, want the per-tool timeout to apply", elapsed)
+	}
+
+	msgs := (*requests)[1].Messages
+	want := []string{`Error: tool "get_weather" timed out after 10ms: context deadline exceeded`, "ok"}
+	for i, content := range want {
+		if got := msgs[len(msgs)-2+i].Content; got != content {
+			t.Errorf("tool message %d = %q, want %q", i, got, content)
+		}
+	}
+}
+
+func TestRunner_MaxIterations(t *testing.T) {
+	mock := &mockRequester{
+		postFunc: func(ctx context.Context, path string, body, result interface{}, opts ...option.RequestOption) error {
+			*result.(*types.ChatCompletion) = *toolCallCompletion(call("c", "get_weather", `{}`))
+			return nil
+		},
+	}
+	runner := NewRunner(NewCompletions(mock),
+		WithMaxIterations(3),
+		WithTool(weatherTool, func(ctx context.Context, arguments string) (string, error) { return "ok", nil }),
+	)
+
+	result, err := runner.Run(context.Background(), &types.CreateChatCompletionRequest{Model: "m"})
+	if !errors.Is(err, ErrMaxIterations) {
+		t.Fatalf("error = %v, want ErrMaxIterations", err)
+	}
+	if result.Iterations != 3 {
+		t.Errorf("iterations = %d, want 3", result.Iterations)
+	}
+}
+
+func TestRunner_Parallel(t *testing.T) {
+	mock, requests := scriptedRequester(t,
+		toolCallCompletion(call("a", "get_weather", `{"city":"A"}`), call("b", "get_weather", `{"city":"B"}`)),
+		textCompletion("done"),
+	)
+
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	runner := NewRunner(NewCompletions(mock),
+		WithTool(weatherTool, func(ctx context.Context, arguments string) (string, error) {
+			n := running.Add(1)
+			if n > peak.Load() {
+				peak.Store(n)
+			}
+			if n == 2 {
+				close(release)
+			}
+			select {
+			case <-release:
+			case <-time.After(time.Second):
+			}
+			running.Add(-1)
+			return arguments, nil
+		}),
+	)
+
+	req := &types.CreateChatCompletionRequest{Model: "m", ParallelToolCalls: option.Ptr(option.Some(true))}
+	if _, err := runner.Run(context.Background(), req); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if peak.Load() != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak.Load())
+	}
+	msgs := (*requests)[1].Messages
+	if msgs[1].ToolCallID != "a" || msgs[1].Content != `{"city":"A"}` || msgs[2].ToolCallID != "b" {
+		t.Errorf("tool messages out of order: %+v", msgs)
+	}
+}
+
+func TestRunner_RunStream(t *testing.T) {
+	streams := []string{
+		`data: {"id":"c1","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}]}
+
+data: {"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Oslo\"}"}}]},"finish_reason":"tool_calls"}]}
+
+data: [DONE]
+
+`,
+		`data: {"id":"c2","choices":[{"index":0,"delta":{"content":"Cold"},"finish_reason":"stop"}]}
+
+data: [DONE]
+
+`,
+	}
+
+	var calls int
+	var sent []*types.CreateChatCompletionRequest
+	mock := &mockRequester{
+		postStreamFunc: func(ctx context.Context, path string, body interface{}, opts ...option.RequestOption) (*http.Response, error) {
+			sent = append(sent, body.(*types.CreateChatCompletionRequest))
+			s := streams[calls]
+			calls++
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(s)), Header: make(http.Header)}, nil
+		},
+	}
+
+	var gotArgs string
+	var chunks int
+	runner := NewRunner(NewCompletions(mock),
+		WithTool(weatherTool, func(ctx context.Context, arguments string) (string, error) {
+			gotArgs = arguments
+			return "-5C", nil
+		}),
+		WithRunnerEvents(func(e RunnerEvent) {
+			if e.Type == RunnerEventChunk {
+				chunks++
+			}
+		}),
+	)
+
+	req := &types.CreateChatCompletionRequest{Model: "m"}
+	result, err := runner.RunStream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RunStream error: %v", err)
+	}
+
+	if gotArgs != `{"city":"Oslo"}` {
+		t.Errorf("tool arguments = %q", gotArgs)
+	}
+	if result.Completion.Choices[0].Message.Content != "Cold" {
+		t.Errorf("final content = %q", result.Completion.Choices[0].Message.Content)
+	}
+	if chunks != 3 {
+		t.Errorf("chunk events = %d, want 3", chunks)
+	}
+	if req.Stream != nil {
+		t.Error("caller's request was modified")
+	}
+	if msgs := sent[1].Messages; len(msgs) != 2 || msgs[1].Content != "-5C" {
+		t.Errorf("second request messages = %+v", msgs)
+	}
+}
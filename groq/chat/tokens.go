@@ -0,0 +1,112 @@
+package chat
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// Token estimation is a heuristic: roughly four characters per token plus a
+// fixed overhead per message. It is intended for budgeting (rate limiting,
+// context trimming), not billing, and errs on the side of overestimating.
+const (
+	charsPerToken       = 4
+	tokensPerMessage    = 4   // Role and message framing
+	tokensPerReply      = 3   // Priming of the assistant reply
+	tokensPerImage      = 765 // Upper bound for a high-detail image tile set
+	tokensPerToolSchema = 8   // Framing of each tool definition
+)
+
+// EstimateTextTokens estimates the number of tokens in s
+func EstimateTextTokens(s string) int {
+	n := utf8.RuneCountInString(s)
+	return (n + charsPerToken - 1) / charsPerToken
+}
+
+// EstimateMessageTokens estimates the prompt tokens used by messages
+func EstimateMessageTokens(messages ...types.ChatCompletionMessageParam) int {
+	total := 0
+	for _, m := range messages {
+		total += tokensPerMessage
+		total += EstimateTextTokens(m.Name)
+		total += estimateContentTokens(m.Content)
+		if m.Reasoning != nil {
+			total += EstimateTextTokens(*m.Reasoning)
+		}
+		for _, tc := range m.ToolCalls {
+			total += EstimateTextTokens(tc.Function.Name) + EstimateTextTokens(tc.Function.Arguments)
+		}
+		if m.FunctionCall != nil {
+			total += EstimateTextTokens(m.FunctionCall.Name) + EstimateTextTokens(m.FunctionCall.Arguments)
+		}
+	}
+	return total
+}
+
+// EstimateTokens estimates the total tokens a chat completion request may
+// consume: the prompt (messages, tools and documents) plus the requested
+// completion budget, if one is set.
+func EstimateTokens(req *types.CreateChatCompletionRequest) int {
+	if req == nil {
+		return 0
+	}
+
+	total := EstimateMessageTokens(req.Messages...) + tokensPerReply
+	for _, tool := range req.Tools {
+		total += tokensPerToolSchema + estimateJSONTokens(tool.Function)
+	}
+	for _, fn := range req.Functions {
+		total += tokensPerToolSchema + estimateJSONTokens(fn)
+	}
+	for _, doc := range req.Documents {
+		total += estimateJSONTokens(doc)
+	}
+
+	switch {
+	case req.MaxCompletionTokens != nil && req.MaxCompletionTokens.IsSet():
+		total += req.MaxCompletionTokens.Value
+	case req.MaxTokens != nil && req.MaxTokens.IsSet():
+		total += req.MaxTokens.Value
+	}
+
+	return total
+}
+
+func estimateContentTokens(content interface{}) int {
+	switch c := content.(type) {
+	case nil:
+		return 0
+	case string:
+		return EstimateTextTokens(c)
+	case []types.ContentPart:
+		total := 0
+		for _, part := range c {
+			total += estimatePartTokens(part)
+		}
+		return total
+	default:
+		return estimateJSONTokens(c)
+	}
+}
+
+func estimatePartTokens(part types.ContentPart) int {
+	switch p := part.(type) {
+	case types.ContentPartText:
+		return EstimateTextTokens(p.Text)
+	case *types.ContentPartText:
+		return EstimateTextTokens(p.Text)
+	case types.ContentPartImage, *types.ContentPartImage:
+		return tokensPerImage
+	default:
+		return estimateJSONTokens(p)
+	}
+}
+
+func estimateJSONTokens(v interface{}) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return (len(b) + charsPerToken - 1) / charsPerToken
+}
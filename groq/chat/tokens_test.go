@@ -0,0 +1,85 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func TestEstimateTextTokens(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"héllo wörld", 3}, // counted in runes, not bytes
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTextTokens(tt.input); got != tt.want {
+			t.Errorf("EstimateTextTokens(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateMessageTokens(t *testing.T) {
+	text := types.ChatCompletionMessageParam{Role: types.RoleUser, Content: "abcdefgh"}
+	if got, want := EstimateMessageTokens(text), tokensPerMessage+2; got != want {
+		t.Errorf("text message = %d, want %d", got, want)
+	}
+
+	multimodal := types.ChatCompletionMessageParam{
+		Role: types.RoleUser,
+		Content: []types.ContentPart{
+			types.ContentPartText{Type: "text", Text: "abcd"},
+			types.ContentPartImage{Type: "image_url", ImageURL: types.ContentPartImage_ImageURL{URL: "https://example.com/a.png"}},
+		},
+	}
+	if got, want := EstimateMessageTokens(multimodal), tokensPerMessage+1+tokensPerImage; got != want {
+		t.Errorf("multimodal message = %d, want %d", got, want)
+	}
+
+	toolCall := types.ChatCompletionMessageParam{
+		Role: types.RoleAssistant,
+		ToolCalls: []types.ToolCall{
+			{ID: "call_1", Type: "function", Function: types.FunctionCall{Name: "get", Arguments: `{"a":1}`}},
+		},
+	}
+	if got, want := EstimateMessageTokens(toolCall), tokensPerMessage+1+2; got != want {
+		t.Errorf("tool call message = %d, want %d", got, want)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	req := &types.CreateChatCompletionRequest{
+		Model: "llama-3.1-8b-instant",
+		Messages: []types.ChatCompletionMessageParam{
+			{Role: types.RoleUser, Content: "abcdefgh"},
+		},
+	}
+
+	base := EstimateTokens(req)
+	if want := tokensPerMessage + 2 + tokensPerReply; base != want {
+		t.Errorf("EstimateTokens() = %d, want %d", base, want)
+	}
+
+	req.MaxCompletionTokens = option.Ptr(option.Some(100))
+	if got := EstimateTokens(req); got != base+100 {
+		t.Errorf("EstimateTokens() with max_completion_tokens = %d, want %d", got, base+100)
+	}
+
+	req.Tools = []types.ChatCompletionTool{
+		{Type: "function", Function: types.FunctionDefinition{Name: "lookup"}},
+	}
+	if got := EstimateTokens(req); got <= base+100 {
+		t.Errorf("EstimateTokens() with tools = %d, want > %d", got, base+100)
+	}
+
+	if got := EstimateTokens(nil); got != 0 {
+		t.Errorf("EstimateTokens(nil) = %d, want 0", got)
+	}
+}
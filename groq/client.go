@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ZaguanLabs/groq-go/groq/audio"
 	"github.com/ZaguanLabs/groq-go/groq/batches"
@@ -19,15 +21,20 @@ import (
 	"github.com/ZaguanLabs/groq-go/groq/files"
 	"github.com/ZaguanLabs/groq-go/groq/internal/form"
 	"github.com/ZaguanLabs/groq-go/groq/internal/querystring"
+	"github.com/ZaguanLabs/groq-go/groq/internal/ratelimit"
 	"github.com/ZaguanLabs/groq-go/groq/internal/retry"
 	"github.com/ZaguanLabs/groq-go/groq/models"
 	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
 )
 
 // Client is the Groq API client
 type Client struct {
 	httpClient *http.Client
 	config     *ClientConfig
+	limiter    *ratelimit.Limiter
+	logger     *requestLogger // nil unless structured logging is enabled
+	send       MiddlewareNext // httpClient.Do wrapped in the middleware chain
 
 	// Resources
 	Chat       *chat.Completions
@@ -63,8 +70,9 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 	}
 
 	if cfg.HTTPClient == nil {
+		// No http.Client.Timeout: it would also cut off long streams.
+		// Timeouts are applied per request via contexts (see timeout.go).
 		cfg.HTTPClient = &http.Client{
-			Timeout: cfg.Timeout,
 			Transport: &http.Transport{
 				MaxIdleConns:        100,
 				MaxIdleConnsPerHost: 20,
@@ -76,11 +84,27 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		}
 	}
 
+	if cfg.LogHandler == nil {
+		cfg.LogHandler = envLogHandler()
+		cfg.LogBodies = cfg.LogBodies || os.Getenv("GROQ_LOG") == "debug"
+	}
+
 	c := &Client{
 		httpClient: cfg.HTTPClient,
 		config:     cfg,
 	}
 
+	middleware := cfg.Middleware
+	if cfg.LogHandler != nil {
+		// Innermost, so records show what is actually sent
+		c.logger = newRequestLogger(cfg.LogHandler, cfg.APIKey, cfg.LogBodies)
+		middleware = append(middleware[:len(middleware):len(middleware)], c.logger.middleware)
+	}
+	c.send = chainMiddleware(c.httpClient.Do, middleware)
+	if cfg.RateLimitRPM > 0 || cfg.RateLimitTPM > 0 {
+		c.limiter = ratelimit.New(cfg.RateLimitRPM, cfg.RateLimitTPM)
+	}
+
 	// Initialize resources
 	c.Chat = chat.NewCompletions(c)
 	c.Embeddings = embeddings.New(c)
@@ -109,6 +133,13 @@ func (c *Client) Post(ctx context.Context, path string, body, result interface{}
 		opt(reqOpts)
 	}
 
+	if err := c.validateRequest(ctx, body); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, reqOpts)
+	defer cancel()
+
 	// Build request
 	req, err := c.buildRequest(ctx, http.MethodPost, path, body, reqOpts)
 	if err != nil {
@@ -116,7 +147,7 @@ func (c *Client) Post(ctx context.Context, path string, body, result interface{}
 	}
 
 	// Execute with retry
-	resp, err := c.doWithRetry(ctx, req, reqOpts)
+	resp, err := c.doWithRetry(ctx, req, reqOpts, callInfo{body: body})
 	if err != nil {
 		return err
 	}
@@ -145,7 +176,7 @@ func (c *Client) Post(ctx context.Context, path string, body, result interface{}
 	// Parse response
 	if result != nil {
 		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return &GroqError{Message: fmt.Sprintf("error decoding response: %v", err)}
+			return decodeError(err)
 		}
 	}
 
@@ -162,17 +193,27 @@ func (c *Client) PostStream(ctx context.Context, path string, body interface{},
 		opt(reqOpts)
 	}
 
+	if err := c.validateRequest(ctx, body); err != nil {
+		return nil, err
+	}
+
+	// The call context lives until the caller closes the response body
+	ctx, cancel := withTimeout(ctx, reqOpts)
+
 	// Build request
 	req, err := c.buildRequest(ctx, http.MethodPost, path, body, reqOpts)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
 	// Execute with retry
-	resp, err := c.doWithRetry(ctx, req, reqOpts)
+	resp, err := c.doWithRetry(ctx, req, reqOpts, callInfo{body: body, stream: true})
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 
 	// Handle errors
 	if resp.StatusCode >= 400 {
@@ -210,12 +251,15 @@ func (c *Client) Get(ctx context.Context, path string, result interface{}, opts
 		opt(reqOpts)
 	}
 
+	ctx, cancel := withTimeout(ctx, reqOpts)
+	defer cancel()
+
 	req, err := c.buildRequest(ctx, http.MethodGet, path, nil, reqOpts)
 	if err != nil {
 		return err
 	}
 
-	return c.execute(ctx, req, result, reqOpts)
+	return c.execute(ctx, req, result, reqOpts, callInfo{})
 }
 
 // GetStream sends a GET request and returns the raw response
@@ -228,15 +272,21 @@ func (c *Client) GetStream(ctx context.Context, path string, opts ...option.Requ
 		opt(reqOpts)
 	}
 
+	// The call context lives until the caller closes the response body
+	ctx, cancel := withTimeout(ctx, reqOpts)
+
 	req, err := c.buildRequest(ctx, http.MethodGet, path, nil, reqOpts)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	resp, err := c.doWithRetry(ctx, req, reqOpts)
+	resp, err := c.doWithRetry(ctx, req, reqOpts, callInfo{stream: true})
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
@@ -256,17 +306,20 @@ func (c *Client) Delete(ctx context.Context, path string, result interface{}, op
 		opt(reqOpts)
 	}
 
+	ctx, cancel := withTimeout(ctx, reqOpts)
+	defer cancel()
+
 	req, err := c.buildRequest(ctx, http.MethodDelete, path, nil, reqOpts)
 	if err != nil {
 		return err
 	}
 
-	return c.execute(ctx, req, result, reqOpts)
+	return c.execute(ctx, req, result, reqOpts, callInfo{})
 }
 
 // execute handles the common request execution logic
-func (c *Client) execute(ctx context.Context, req *http.Request, result interface{}, opts *option.RequestOptions) error {
-	resp, err := c.doWithRetry(ctx, req, opts)
+func (c *Client) execute(ctx context.Context, req *http.Request, result interface{}, opts *option.RequestOptions, call callInfo) error {
+	resp, err := c.doWithRetry(ctx, req, opts, call)
 	if err != nil {
 		return err
 	}
@@ -295,7 +348,7 @@ func (c *Client) execute(ctx context.Context, req *http.Request, result interfac
 
 	if result != nil && resp.StatusCode != http.StatusNoContent {
 		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return &GroqError{Message: fmt.Sprintf("error decoding response: %v", err)}
+			return decodeError(err)
 		}
 	}
 
@@ -312,27 +365,49 @@ func (c *Client) PostForm(ctx context.Context, path string, formStruct interface
 		opt(reqOpts)
 	}
 
-	// Encode form
-	enc := form.NewEncoder()
-	contentType, bodyReader, err := enc.Encode(formStruct)
+	if err := c.validateRequest(ctx, formStruct); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx, reqOpts)
+	defer cancel()
+
+	// Stream the form so files are not buffered in memory
+	stream, err := form.NewStream(formStruct)
 	if err != nil {
 		return fmt.Errorf("form encode: %w", err)
 	}
+	defer stream.Close()
+	stream.SetProgress(reqOpts.UploadProgress)
 
 	// Set Content-Type header
-	reqOpts.Headers["Content-Type"] = contentType
+	reqOpts.Headers["Content-Type"] = stream.ContentType()
 
 	// Build request
+	bodyReader, err := stream.Open()
+	if err != nil {
+		return err
+	}
 	url := c.buildURL(path, reqOpts)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
 	if err != nil {
 		return err
 	}
+	if n := stream.ContentLength(); n >= 0 {
+		req.ContentLength = n
+	}
+	if stream.Rewindable() {
+		req.GetBody = stream.Open
+	} else {
+		// A file that cannot seek back to its start can only be sent once
+		noRetries := 0
+		reqOpts.MaxRetries = &noRetries
+	}
 
 	// Set other headers
 	c.setHeaders(req, reqOpts)
 
-	return c.execute(ctx, req, result, reqOpts)
+	return c.execute(ctx, req, result, reqOpts, callInfo{body: formStruct})
 }
 
 func (c *Client) buildRequest(ctx context.Context, method, path string, body interface{}, opts *option.RequestOptions) (*http.Request, error) {
@@ -375,8 +450,8 @@ func (c *Client) buildURL(path string, opts *option.RequestOptions) string {
 	if len(query) > 0 {
 		if qs, err := querystring.Stringify(query); err == nil && qs != "" {
 			u += "?" + qs
-		} else if err != nil && c.config.Logger != nil {
-			c.config.Logger.Warn("Failed to serialize query parameters: %v", err)
+		} else if err != nil {
+			c.warn(context.Background(), "failed to serialize query parameters", err)
 		}
 	}
 
@@ -413,27 +488,106 @@ func (c *Client) setHeaders(req *http.Request, opts *option.RequestOptions) {
 	}
 }
 
-func (c *Client) doWithRetry(ctx context.Context, req *http.Request, opts *option.RequestOptions) (*http.Response, error) {
+// callInfo describes an API call as it flows through doWithRetry
+type callInfo struct {
+	body   interface{} // Request body before encoding, nil for GET and DELETE
+	stream bool        // Streaming timeout semantics apply (see timeout.go)
+}
+
+// doWithRetry sends req, retrying according to the retry policy
+func (c *Client) doWithRetry(ctx context.Context, req *http.Request, opts *option.RequestOptions, call callInfo) (*http.Response, error) {
 	maxRetries := c.config.MaxRetries
 	if opts.MaxRetries != nil {
 		maxRetries = *opts.MaxRetries
 	}
 
 	retryCfg := retry.Config{
-		MaxRetries:  maxRetries,
-		ShouldRetry: retry.DefaultShouldRetry,
+		MaxRetries:       maxRetries,
+		ShouldRetry:      retry.DefaultShouldRetry,
+		ShouldRetryError: retry.DefaultShouldRetryError,
+	}
+
+	var tokens int
+	if c.limiter != nil {
+		tokens = estimateTokens(call.body)
 	}
 
-	return retry.Do(ctx, retryCfg, func() (*http.Response, error) {
-		// Clone request for each attempt (rewind body if needed)
-		// For now assuming body is bytes.Reader which is seekable or we need to handle it
-		// http.NewRequestWithContext wraps bytes.Reader properly.
-		// But if we retry, we need to ensure the body is read from start.
-		// `http.Client.Do` handles rewinding `GetBody` if set.
-		// `http.NewRequest` sets `GetBody` for `bytes.Reader`.
+	start := time.Now()
+	attempt := 0
+	resp, err := retry.Do(ctx, retryCfg, func() (*http.Response, error) {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx, tokens); err != nil {
+				return nil, err
+			}
+		}
 
-		return c.httpClient.Do(req)
+		attemptReq := req
+		if attempt > 0 {
+			// The previous attempt consumed the body, so clone the request
+			// and rewind the body via GetBody (set by http.NewRequest for
+			// bytes.Reader and bytes.Buffer bodies).
+			attemptReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+		resp, err := c.doAttempt(attemptReq, opts, call.stream, attempt)
+		attempt++
+		if err == nil && c.limiter != nil {
+			c.limiter.Observe(limiterState(resp))
+		}
+		return resp, err
 	})
+	if err != nil {
+		return nil, newConnectionError(req, err)
+	}
+
+	if len(opts.OnResponse) > 0 {
+		info := &option.ResponseInfo{
+			Response:  resp,
+			RequestID: resp.Header.Get(HeaderRequestID),
+			Attempts:  attempt,
+			Elapsed:   time.Since(start),
+		}
+		for _, fn := range opts.OnResponse {
+			fn(info)
+		}
+	}
+	return resp, nil
+}
+
+// decodeError reports a failure to decode a response body. Timeouts while
+// reading the body are returned as-is.
+func decodeError(err error) error {
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return timeoutErr
+	}
+	return &GroqError{Message: fmt.Sprintf("error decoding response: %v", err)}
+}
+
+// newConnectionError converts a transport error into a ConnectionError or
+// TimeoutError. Cancellation by the caller is returned unchanged.
+func newConnectionError(req *http.Request, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	if retry.IsTimeout(err) {
+		return &TimeoutError{ConnectionError: ConnectionError{
+			GroqError: GroqError{Message: fmt.Sprintf("Request timed out: %v", err), Request: req},
+			Err:       err,
+		}}
+	}
+
+	return &ConnectionError{
+		GroqError: GroqError{Message: fmt.Sprintf("Connection error: %v", err), Request: req},
+		Err:       err,
+	}
 }
 
 func (c *Client) handleError(resp *http.Response) error {
@@ -450,6 +604,24 @@ func (c *Client) handleError(resp *http.Response) error {
 		Response:   resp,
 		StatusCode: resp.StatusCode,
 		Body:       body,
+		RateLimit:  ParseRateLimitInfo(resp.Header),
+	}
+
+	var envelope types.ErrorResponse
+	if err := json.Unmarshal(respBytes, &envelope); err == nil && envelope.Error != nil {
+		obj := envelope.Error
+		apiErr.ErrorObject = obj
+		apiErr.Type = obj.Type
+		apiErr.FailedGeneration = obj.FailedGeneration
+		if obj.Code != nil {
+			apiErr.Code = fmt.Sprint(obj.Code)
+		}
+		if obj.Param != nil {
+			apiErr.Param = fmt.Sprint(obj.Param)
+		}
+		if obj.Message != "" {
+			apiErr.Message = obj.Message
+		}
 	}
 
 	switch resp.StatusCode {
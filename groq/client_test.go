@@ -2,15 +2,23 @@ package groq
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
 )
 
 func TestNewClient_Defaults(t *testing.T) {
@@ -633,3 +641,480 @@ func TestClient_BaseURLFromEnv(t *testing.T) {
 		t.Errorf("BaseURL = %s, want https://custom.api.com", c.config.BaseURL)
 	}
 }
+
+func TestClient_RetryResendsBody(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"key":"value"}` {
+			t.Errorf("attempt %d body = %s, want {\"key\":\"value\"}", attempts, body)
+		}
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(2),
+	)
+
+	var result map[string]interface{}
+	if err := c.Post(context.Background(), "/test", map[string]string{"key": "value"}, &result); err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestClient_PostFormRetryRewindsFile(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if r.ContentLength != int64(len(body)) {
+			t.Errorf("attempt %d Content-Length = %d, body is %d bytes", attempts, r.ContentLength, len(body))
+		}
+		if !strings.Contains(string(body), "line 1\nline 2\n") || !strings.Contains(string(body), `filename="input.jsonl"`) {
+			t.Errorf("attempt %d body = %s", attempts, body)
+		}
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "input.jsonl")
+	if err := os.WriteFile(path, []byte("line 1\nline 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(2),
+	)
+
+	type FormData struct {
+		File    io.Reader `json:"file"`
+		Purpose string    `json:"purpose"`
+	}
+
+	var progress [][2]int64
+	err = c.PostForm(context.Background(), "/test", &FormData{File: f, Purpose: "batch"}, nil,
+		option.WithUploadProgress(func(sent, total int64) {
+			progress = append(progress, [2]int64{sent, total})
+		}))
+	if err != nil {
+		t.Fatalf("PostForm error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+	// Each attempt reports the whole file
+	want := [][2]int64{{14, 14}, {14, 14}}
+	if !reflect.DeepEqual(progress, want) {
+		t.Errorf("progress = %v, want %v", progress, want)
+	}
+}
+
+func TestClient_PostFormBodyHashingMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if got := r.Header.Get("X-Content-Sha256"); got != hex.EncodeToString(sum[:]) {
+			t.Errorf("X-Content-Sha256 = %s, body hashes to %x", got, sum)
+		}
+		if !strings.Contains(string(body), "line 1\nline 2\n") {
+			t.Errorf("body = %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "input.jsonl")
+	if err := os.WriteFile(path, []byte("line 1\nline 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Hashes the body through GetBody, as request signing does
+	signer := func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, body); err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Content-Sha256", hex.EncodeToString(h.Sum(nil)))
+		return next(req)
+	}
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMiddleware(signer),
+	)
+
+	type FormData struct {
+		File    io.Reader `json:"file"`
+		Purpose string    `json:"purpose"`
+	}
+	if err := c.PostForm(context.Background(), "/test", &FormData{File: f, Purpose: "batch"}, nil); err != nil {
+		t.Fatalf("PostForm error: %v", err)
+	}
+}
+
+func TestClient_PostFormUnseekableNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(2),
+	)
+
+	type FormData struct {
+		File io.Reader `json:"file"`
+	}
+
+	// An io.MultiReader hides the Seek method of the strings.Reader
+	err := c.PostForm(context.Background(), "/test", &FormData{File: io.MultiReader(strings.NewReader("content"))}, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestClient_RetryConnectionDropped(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			// Drop the connection without writing a response
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(2),
+	)
+
+	var result map[string]interface{}
+	if err := c.Post(context.Background(), "/test", map[string]string{}, &result); err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestClient_ConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(1),
+	)
+
+	err := c.Post(context.Background(), "/test", nil, nil)
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("expected *ConnectionError, got %T: %v", err, err)
+	}
+	if connErr.Err == nil {
+		t.Error("expected underlying error to be set")
+	}
+}
+
+func TestClient_TimeoutError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+		WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}),
+	)
+
+	err := c.Post(context.Background(), "/test", nil, nil)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError, got %T: %v", err, err)
+	}
+}
+
+func TestClient_RequestTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(3),
+	)
+
+	start := time.Now()
+	err := c.Post(context.Background(), "/test", nil, nil, option.WithRequestTimeout(100*time.Millisecond))
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError, got %T: %v", err, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %v, want total deadline of ~100ms", elapsed)
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("attempts = %d, want 1 (total deadline stops retries)", n)
+	}
+}
+
+func TestClient_AttemptTimeoutRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(2),
+	)
+
+	var result map[string]interface{}
+	err := c.Post(context.Background(), "/test", nil, &result, option.WithRequestAttemptTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestClient_StreamOutlivesClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 5; i++ {
+			w.Write([]byte("data: chunk\n\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithTimeout(100*time.Millisecond),
+	)
+
+	resp, err := c.PostStream(context.Background(), "/stream", nil)
+	if err != nil {
+		t.Fatalf("PostStream error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading stream: %v", err)
+	}
+	if got := strings.Count(string(body), "chunk"); got != 5 {
+		t.Errorf("received %d chunks, want 5", got)
+	}
+}
+
+func TestClient_StreamIdleTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: chunk\n\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	resp, err := c.PostStream(context.Background(), "/stream", nil,
+		option.WithRequestStreamIdleTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("PostStream error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError, got %T: %v", err, err)
+	}
+}
+
+func TestClient_WithResponseInto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-request-id", "req_123")
+		w.Write([]byte(`{"object":"list","data":[{"id":"llama-3.1-8b-instant","object":"model"}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var resp *http.Response
+	list, err := c.Models.List(context.Background(), option.WithResponseInto(&resp))
+	if err != nil {
+		t.Fatalf("Models.List error: %v", err)
+	}
+	if len(list.Data) != 1 {
+		t.Errorf("len(Data) = %d, want 1", len(list.Data))
+	}
+	if resp == nil {
+		t.Fatal("response not captured")
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if id := resp.Header.Get("x-request-id"); id != "req_123" {
+		t.Errorf("x-request-id = %q, want req_123", id)
+	}
+}
+
+func TestClient_WithResponseInfoInto(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("x-request-id", fmt.Sprintf("req_%d", attempts))
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"chatcmpl-1","choices":[]}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(2),
+	)
+
+	var info option.ResponseInfo
+	_, err := c.Chat.Create(context.Background(), &types.CreateChatCompletionRequest{
+		Model:    "llama-3.1-8b-instant",
+		Messages: []types.ChatCompletionMessageParam{{Role: types.RoleUser, Content: "Hi"}},
+	}, option.WithResponseInfoInto(&info))
+	if err != nil {
+		t.Fatalf("Chat.Create error: %v", err)
+	}
+
+	if info.RequestID != "req_2" {
+		t.Errorf("RequestID = %q, want req_2", info.RequestID)
+	}
+	if info.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", info.Attempts)
+	}
+	if info.Elapsed <= 0 {
+		t.Error("Elapsed not recorded")
+	}
+	if info.Response == nil || info.Response.StatusCode != http.StatusOK {
+		t.Errorf("Response = %+v, want 200 response", info.Response)
+	}
+}
+
+func TestClient_WithResponseIntoOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req_err")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"message":"not found"}}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var resp *http.Response
+	_, err := c.Files.Retrieve(context.Background(), "file-123", option.WithResponseInto(&resp))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if resp == nil || resp.Header.Get("x-request-id") != "req_err" {
+		t.Error("response of failed request not captured")
+	}
+}
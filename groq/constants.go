@@ -7,7 +7,7 @@ import (
 
 const (
 	// Version is the current version of the SDK
-	Version = "1.0.0"
+	Version = "1.1.0"
 
 	// DefaultBaseURL is the default API endpoint
 	DefaultBaseURL = "https://api.groq.com"
@@ -16,6 +16,7 @@ const (
 	DefaultMaxRetries = 2
 
 	// Headers
+	HeaderRequestID    = "x-request-id"
 	HeaderRawResponse  = "X-Stainless-Raw-Response"
 	HeaderOverrideCast = "____stainless_override_cast_to"
 
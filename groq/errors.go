@@ -1,8 +1,24 @@
 package groq
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// Error codes returned by the API in the "code" field of an error body
+const (
+	ErrorCodeRateLimitExceeded     = "rate_limit_exceeded"
+	ErrorCodeContextLengthExceeded = "context_length_exceeded"
+	ErrorCodeJSONValidateFailed    = "json_validate_failed"
+	ErrorCodeToolUseFailed         = "tool_use_failed"
+	ErrorCodeModelNotFound         = "model_not_found"
+	ErrorCodeModelDecommissioned   = "model_decommissioned"
+	ErrorCodeInvalidAPIKey         = "invalid_api_key"
+	ErrorCodeRequestTooLarge       = "request_too_large"
 )
 
 // GroqError is the base error type for the SDK
@@ -19,10 +35,88 @@ type APIError struct {
 	Response   *http.Response
 	StatusCode int
 	Body       interface{} // Parsed JSON or raw string
+
+	// Fields decoded from the {"error": {...}} envelope, empty if the body
+	// did not contain one
+	ErrorObject      *types.ErrorObject
+	Code             string
+	Type             string
+	Param            string
+	FailedGeneration string
+
+	// RateLimit holds the rate limit headers of the response, if present
+	RateLimit *RateLimitInfo
 }
 
+// Error formats the decoded error message, type and code, or the body if it
+// had no error envelope
 func (e *APIError) Error() string {
-	return fmt.Sprintf("Error code: %d - %v", e.StatusCode, e.Body)
+	if e.ErrorObject == nil {
+		if e.Body == nil && e.Message != "" {
+			// Not JSON; Message holds the raw body
+			return e.Message
+		}
+		return fmt.Sprintf("Error code: %d - %v", e.StatusCode, e.Body)
+	}
+
+	msg := fmt.Sprintf("Error code: %d - %s", e.StatusCode, e.Message)
+	var details []string
+	if e.Type != "" {
+		details = append(details, "type: "+e.Type)
+	}
+	if e.Code != "" {
+		details = append(details, "code: "+e.Code)
+	}
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, ", ") + ")"
+	}
+	return msg
+}
+
+func (e *APIError) apiError() *APIError { return e }
+
+// AsAPIError finds the first APIError in err's chain, whichever status
+// specific type (BadRequestError, RateLimitError, ...) wraps it
+func AsAPIError(err error) (*APIError, bool) {
+	var target interface{ apiError() *APIError }
+	if errors.As(err, &target) {
+		return target.apiError(), true
+	}
+	return nil, false
+}
+
+// IsRateLimit reports whether err is a 429 response or carries the
+// rate_limit_exceeded error code
+func IsRateLimit(err error) bool {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return true
+	}
+	return hasErrorCode(err, ErrorCodeRateLimitExceeded)
+}
+
+// IsContextLengthExceeded reports whether the request exceeded the model's
+// context window
+func IsContextLengthExceeded(err error) bool {
+	return hasErrorCode(err, ErrorCodeContextLengthExceeded)
+}
+
+// IsJSONValidationFailed reports whether the model output failed JSON mode or
+// JSON schema validation. The rejected output is available in
+// APIError.FailedGeneration.
+func IsJSONValidationFailed(err error) bool {
+	return hasErrorCode(err, ErrorCodeJSONValidateFailed)
+}
+
+// IsToolUseFailed reports whether the model produced an invalid tool call.
+// The rejected output is available in APIError.FailedGeneration.
+func IsToolUseFailed(err error) bool {
+	return hasErrorCode(err, ErrorCodeToolUseFailed)
+}
+
+func hasErrorCode(err error, code string) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Code == code
 }
 
 // Specific status errors
@@ -52,10 +146,22 @@ type RateLimitError struct{ APIError }
 type InternalServerError struct{ APIError }
 
 // ConnectionError represents a connection error
-type ConnectionError struct{ GroqError }
+type ConnectionError struct {
+	GroqError
+	Err error // Underlying transport error
+}
+
+func (e *ConnectionError) Unwrap() error { return e.Err }
 
 // TimeoutError represents a timeout error
 type TimeoutError struct{ ConnectionError }
 
-// ValidationError represents a validation error
-type ValidationError struct{ GroqError }
+// ValidationError is returned for a request that fails client-side
+// validation; no request was sent
+type ValidationError struct {
+	GroqError
+	Field string // Path of the invalid field, e.g. "messages[0].content"
+	Err   error  // Usually a *types.FieldError
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
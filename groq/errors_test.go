@@ -0,0 +1,137 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_ErrorBodyParsing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Failed to generate JSON.","type":"invalid_request_error","code":"json_validate_failed","param":"response_format","failed_generation":"{\"name\": "}}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	err := c.Post(context.Background(), "/test", nil, nil)
+
+	var badReq *BadRequestError
+	if !errors.As(err, &badReq) {
+		t.Fatalf("expected *BadRequestError, got %T", err)
+	}
+	if badReq.Code != ErrorCodeJSONValidateFailed {
+		t.Errorf("Code = %q, want %q", badReq.Code, ErrorCodeJSONValidateFailed)
+	}
+	if badReq.Type != "invalid_request_error" {
+		t.Errorf("Type = %q, want invalid_request_error", badReq.Type)
+	}
+	if badReq.Param != "response_format" {
+		t.Errorf("Param = %q, want response_format", badReq.Param)
+	}
+	if badReq.FailedGeneration != `{"name": ` {
+		t.Errorf("FailedGeneration = %q", badReq.FailedGeneration)
+	}
+	if badReq.Message != "Failed to generate JSON." {
+		t.Errorf("Message = %q, want Failed to generate JSON.", badReq.Message)
+	}
+	if badReq.ErrorObject == nil {
+		t.Error("ErrorObject not set")
+	}
+	if !IsJSONValidationFailed(err) {
+		t.Error("IsJSONValidationFailed() = false, want true")
+	}
+	want := "Error code: 400 - Failed to generate JSON. (type: invalid_request_error, code: json_validate_failed)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestClient_ErrorBodyWithoutEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`upstream failure`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+	)
+
+	err := c.Get(context.Background(), "/test", nil)
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected APIError, got %T", err)
+	}
+	if apiErr.ErrorObject != nil || apiErr.Code != "" {
+		t.Errorf("expected no decoded error object, got %+v", apiErr.ErrorObject)
+	}
+	if err.Error() != "Error code: 500 - upstream failure" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestAPIError_ErrorWithoutEnvelope(t *testing.T) {
+	err := &APIError{StatusCode: 400, Body: map[string]interface{}{"detail": "bad"}}
+	if got := err.Error(); got != "Error code: 400 - map[detail:bad]" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	newErr := func(status int, code string) *APIError {
+		return &APIError{StatusCode: status, Code: code}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		fn   func(error) bool
+		want bool
+	}{
+		{"rate limit status", &RateLimitError{APIError: *newErr(429, "")}, IsRateLimit, true},
+		{"rate limit code", &BadRequestError{APIError: *newErr(400, ErrorCodeRateLimitExceeded)}, IsRateLimit, true},
+		{"rate limit wrapped", fmt.Errorf("call failed: %w", &RateLimitError{}), IsRateLimit, true},
+		{"not rate limit", &BadRequestError{APIError: *newErr(400, "")}, IsRateLimit, false},
+		{"context length", &BadRequestError{APIError: *newErr(400, ErrorCodeContextLengthExceeded)}, IsContextLengthExceeded, true},
+		{"json validation", &BadRequestError{APIError: *newErr(400, ErrorCodeJSONValidateFailed)}, IsJSONValidationFailed, true},
+		{"tool use", &BadRequestError{APIError: *newErr(400, ErrorCodeToolUseFailed)}, IsToolUseFailed, true},
+		{"other code", &BadRequestError{APIError: *newErr(400, ErrorCodeToolUseFailed)}, IsContextLengthExceeded, false},
+		{"non api error", errors.New("boom"), IsContextLengthExceeded, false},
+		{"nil", nil, IsRateLimit, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsAPIError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &NotFoundError{APIError: APIError{StatusCode: 404}})
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatal("AsAPIError() ok = false, want true")
+	}
+	if apiErr.StatusCode != 404 {
+		t.Errorf("StatusCode = %d, want 404", apiErr.StatusCode)
+	}
+
+	if _, ok := AsAPIError(errors.New("boom")); ok {
+		t.Error("AsAPIError() ok = true for non-API error")
+	}
+}
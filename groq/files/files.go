@@ -2,14 +2,25 @@ package files
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"math/rand/v2"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/pagination"
 	"github.com/ZaguanLabs/groq-go/groq/types"
 )
 
+// ErrSizeMismatch is returned by DownloadTo when the downloaded content
+// differs in size from the file
+var ErrSizeMismatch = errors.New("files: downloaded size does not match the file size")
+
 // Requester defines the interface for sending requests
 type Requester interface {
 	PostForm(ctx context.Context, path string, formStruct interface{}, result interface{}, opts ...option.RequestOption) error
@@ -38,9 +49,21 @@ func (f *Files) Create(ctx context.Context, req *types.CreateFileRequest, opts .
 	return &result, nil
 }
 
-// List lists files
-func (f *Files) List(ctx context.Context, opts ...option.RequestOption) (*types.FileListResponse, error) {
+// List lists files, optionally filtered by purpose, one page at a time
+func (f *Files) List(ctx context.Context, req *types.ListFilesRequest, opts ...option.RequestOption) (*types.FileListResponse, error) {
 	var result types.FileListResponse
+	if req != nil {
+		if req.After != nil && req.After.IsSet() {
+			opts = append(opts, option.WithRequestQuery("after", req.After.Value))
+		}
+		if req.Limit != nil && req.Limit.IsSet() {
+			opts = append(opts, option.WithRequestQuery("limit", strconv.Itoa(req.Limit.Value)))
+		}
+		if req.Purpose != nil && req.Purpose.IsSet() {
+			opts = append(opts, option.WithRequestQuery("purpose", string(req.Purpose.Value)))
+		}
+	}
+
 	err := f.requester.Get(ctx, "/openai/v1/files", &result, opts...)
 	if err != nil {
 		return nil, err
@@ -48,6 +71,37 @@ func (f *Files) List(ctx context.Context, opts ...option.RequestOption) (*types.
 	return &result, nil
 }
 
+// ListAutoPaging iterates over all files, starting after req.After and
+// fetching req.Limit files per page
+//
+//	for file, err := range client.Files.ListAutoPaging(ctx, nil).All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(file.ID, file.Filename)
+//	}
+func (f *Files) ListAutoPaging(ctx context.Context, req *types.ListFilesRequest, opts ...option.RequestOption) *pagination.Iter[types.FileObject] {
+	return pagination.NewIter(ctx, func(ctx context.Context, cursor string) (*pagination.Page[types.FileObject], error) {
+		var pageReq types.ListFilesRequest
+		if req != nil {
+			pageReq = *req
+		}
+		if cursor != "" {
+			pageReq.After = option.Ptr(option.Some(cursor))
+		}
+
+		list, err := f.List(ctx, &pageReq, opts...)
+		if err != nil {
+			return nil, err
+		}
+		page := &pagination.Page[types.FileObject]{Data: list.Data, HasMore: list.HasMore, NextCursor: list.LastID}
+		if page.NextCursor == "" && len(list.Data) > 0 {
+			page.NextCursor = list.Data[len(list.Data)-1].ID
+		}
+		return page, nil
+	})
+}
+
 // Retrieve retrieves a file
 func (f *Files) Retrieve(ctx context.Context, fileID string, opts ...option.RequestOption) (*types.FileObject, error) {
 	var result types.FileObject
@@ -76,3 +130,65 @@ func (f *Files) Content(ctx context.Context, fileID string, opts ...option.Reque
 	}
 	return resp.Body, nil
 }
+
+// DownloadTo writes the content of a file to path. The content is written
+// to a temporary file in the same directory, checked against the size of
+// the file, and renamed to path, so path is never left partially written.
+// A new file gets the mode os.Create would give it; an existing file keeps
+// its mode.
+func (f *Files) DownloadTo(ctx context.Context, fileID, path string, opts ...option.RequestOption) (err error) {
+	file, err := f.Retrieve(ctx, fileID, opts...)
+	if err != nil {
+		return err
+	}
+	content, err := f.Content(ctx, fileID, opts...)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	tmp, err := createTemp(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := io.Copy(tmp, content)
+	if err != nil {
+		return err
+	}
+	if n != file.Bytes {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrSizeMismatch, n, file.Bytes)
+	}
+	if info, statErr := os.Stat(path); statErr == nil {
+		if err = tmp.Chmod(info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// createTemp creates a temporary file next to path. Unlike os.CreateTemp,
+// which restricts the file to its owner, it uses mode 0666 before the umask.
+func createTemp(path string) (*os.File, error) {
+	dir, base := filepath.Split(path)
+	for i := 0; i < 10000; i++ {
+		name := filepath.Join(dir, "."+base+"."+strconv.FormatUint(uint64(rand.Uint32()), 10)+".tmp")
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+		if !errors.Is(err, fs.ErrExist) {
+			return f, err
+		}
+	}
+	return nil, fmt.Errorf("files: cannot create a temporary file for %s", path)
+}
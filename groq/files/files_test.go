@@ -6,6 +6,8 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -197,7 +199,7 @@ func TestFiles_List(t *testing.T) {
 			}
 
 			f := New(mock)
-			resp, err := f.List(context.Background())
+			resp, err := f.List(context.Background(), nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -478,3 +480,204 @@ func TestFiles_WithContext(t *testing.T) {
 		t.Fatal("expected context cancellation error")
 	}
 }
+
+func TestFiles_ListAutoPaging(t *testing.T) {
+	listPages := map[string]*types.FileListResponse{
+		"file-0": {Data: []types.FileObject{{ID: "file-1"}, {ID: "file-2"}}, LastID: "file-2", HasMore: true},
+		"file-2": {Data: []types.FileObject{{ID: "file-3"}}},
+	}
+
+	var queries []map[string]string
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			if path != "/openai/v1/files" {
+				t.Errorf("unexpected path: %s", path)
+			}
+			var o option.RequestOptions
+			for _, opt := range opts {
+				opt(&o)
+			}
+			queries = append(queries, o.QueryParams)
+			page, ok := listPages[o.QueryParams["after"]]
+			if !ok {
+				return errors.New("unexpected cursor")
+			}
+			*result.(*types.FileListResponse) = *page
+			return nil
+		},
+	}
+
+	f := New(mock)
+	files, err := f.ListAutoPaging(context.Background(), &types.ListFilesRequest{
+		After: option.Ptr(option.Some("file-0")),
+		Limit: option.Ptr(option.Some(2)),
+	}).Collect()
+	if err != nil {
+		t.Fatalf("ListAutoPaging error: %v", err)
+	}
+	if len(files) != 3 || files[2].ID != "file-3" {
+		t.Errorf("files = %+v", files)
+	}
+	if len(queries) != 2 || queries[1]["after"] != "file-2" || queries[1]["limit"] != "2" {
+		t.Errorf("queries = %v", queries)
+	}
+}
+
+func TestFiles_ListAutoPagingError(t *testing.T) {
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			return errors.New("list failed")
+		},
+	}
+
+	it := New(mock).ListAutoPaging(context.Background(), nil)
+	if it.Next() {
+		t.Error("Next() = true on error")
+	}
+	if it.Err() == nil || !strings.Contains(it.Err().Error(), "list failed") {
+		t.Errorf("Err() = %v", it.Err())
+	}
+}
+
+func TestFiles_ListPurpose(t *testing.T) {
+	var query map[string]string
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			var o option.RequestOptions
+			for _, opt := range opts {
+				opt(&o)
+			}
+			query = o.QueryParams
+			return nil
+		},
+	}
+
+	f := New(mock)
+	_, err := f.List(context.Background(), &types.ListFilesRequest{
+		Purpose: option.Ptr(option.Some(types.FilePurposeBatchOutput)),
+		Limit:   option.Ptr(option.Some(20)),
+	})
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if query["purpose"] != "batch_output" || query["limit"] != "20" {
+		t.Errorf("query = %v", query)
+	}
+}
+
+func TestFiles_DownloadTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		bytes   int64
+		content string
+		wantErr error
+	}{
+		{name: "matching size", bytes: 11, content: "{\"a\":1}\n{}\n"},
+		{name: "truncated download", bytes: 20, content: "{\"a\":1}\n", wantErr: ErrSizeMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockRequester{
+				getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+					if path != "/openai/v1/files/file-123" {
+						t.Errorf("unexpected path: %s", path)
+					}
+					*result.(*types.FileObject) = types.FileObject{ID: "file-123", Bytes: tt.bytes}
+					return nil
+				},
+				getStreamFunc: func(ctx context.Context, path string, opts ...option.RequestOption) (*http.Response, error) {
+					return &http.Response{Body: io.NopCloser(strings.NewReader(tt.content))}, nil
+				},
+			}
+
+			dir := t.TempDir()
+			path := dir + "/output.jsonl"
+			err := New(mock).DownloadTo(context.Background(), "file-123", path)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				// Neither the destination nor the temporary file is left
+				if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+					t.Errorf("directory has %d entries after a failed download", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadTo error: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil || string(data) != tt.content {
+				t.Errorf("file = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestFiles_DownloadToMode(t *testing.T) {
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			*result.(*types.FileObject) = types.FileObject{ID: "file-123", Bytes: 2}
+			return nil
+		},
+		getStreamFunc: func(ctx context.Context, path string, opts ...option.RequestOption) (*http.Response, error) {
+			return &http.Response{Body: io.NopCloser(strings.NewReader("{}"))}, nil
+		},
+	}
+	dir := t.TempDir()
+
+	// A new file gets the mode os.Create gives
+	created, err := os.Create(filepath.Join(dir, "created"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.Close()
+	want, _ := os.Stat(created.Name())
+
+	path := filepath.Join(dir, "new.jsonl")
+	if err := New(mock).DownloadTo(context.Background(), "file-123", path); err != nil {
+		t.Fatalf("DownloadTo error: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != want.Mode().Perm() {
+		t.Errorf("new file mode = %v, want %v", info.Mode().Perm(), want.Mode().Perm())
+	}
+
+	// An existing file keeps its mode
+	existing := filepath.Join(dir, "existing.jsonl")
+	if err := os.WriteFile(existing, nil, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(existing, 0o640)
+	if err := New(mock).DownloadTo(context.Background(), "file-123", existing); err != nil {
+		t.Fatalf("DownloadTo error: %v", err)
+	}
+	if info, err = os.Stat(existing); err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("existing file mode = %v, want -rw-r-----", info.Mode().Perm())
+	}
+}
+
+func TestFiles_DownloadToRetrieveError(t *testing.T) {
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			return errors.New("file not found")
+		},
+	}
+
+	path := t.TempDir() + "/output.jsonl"
+	err := New(mock).DownloadTo(context.Background(), "file-404", path)
+	if err == nil || !strings.Contains(err.Error(), "file not found") {
+		t.Errorf("error = %v", err)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Errorf("destination exists after error: %v", statErr)
+	}
+}
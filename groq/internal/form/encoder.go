@@ -28,14 +28,39 @@ func NewEncoder() *Encoder {
 
 // Encode struct to multipart
 func (e *Encoder) Encode(v interface{}) (string, io.Reader, error) {
+	parts, err := collect(v)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := writeParts(e.w, parts, copyFile); err != nil {
+		return "", nil, err
+	}
+	return e.w.FormDataContentType(), e.b, nil
+}
+
+// part is a field of a form: a value, or a file if file is set
+type part struct {
+	name     string
+	value    string
+	file     io.Reader
+	filename string
+	offset   int64       // Start position of seekable files
+	size     int64       // Remaining bytes of the file, -1 if unknown
+	at       io.ReaderAt // Reads a seekable file by offset, set by NewStream
+}
+
+// collect converts the fields of struct v into form parts, using json tags
+// as field names
+func collect(v interface{}) ([]part, error) {
 	val := reflect.ValueOf(v)
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
 	}
 	if val.Kind() != reflect.Struct {
-		return "", nil, fmt.Errorf("form encode: expected struct, got %v", val.Kind())
+		return nil, fmt.Errorf("form encode: expected struct, got %v", val.Kind())
 	}
 
+	var parts []part
 	t := val.Type()
 	for i := 0; i < val.NumField(); i++ {
 		field := t.Field(i)
@@ -63,43 +88,77 @@ func (e *Encoder) Encode(v interface{}) (string, io.Reader, error) {
 			value = opt.FieldByName("Value")
 		}
 
-		if err := e.writeField(name, value.Interface()); err != nil {
-			return "", nil, err
+		// Unset io.Reader and interface{} fields, such as File when URL is
+		// used instead
+		if value.Kind() == reflect.Interface && value.IsNil() {
+			continue
+		}
+
+		switch val := value.Interface().(type) {
+		case *os.File:
+			parts = append(parts, filePart(name, filepath.Base(val.Name()), val))
+		case io.Reader:
+			// API usually requires filename for file uploads, which a plain
+			// io.Reader does not have
+			parts = append(parts, filePart(name, "file.bin", val))
+		default:
+			// Primitive
+			parts = append(parts, part{name: name, value: fmt.Sprint(val)})
 		}
 	}
+	return parts, nil
+}
 
-	if err := e.w.Close(); err != nil {
-		return "", nil, err
+// filePart records the start position and size of seekable files so they
+// can be rewound and their length computed
+func filePart(name, filename string, r io.Reader) part {
+	p := part{name: name, file: r, filename: filename, size: -1}
+	switch f := r.(type) {
+	case io.Seeker:
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			p.offset = -1
+			break
+		}
+		end, err := f.Seek(0, io.SeekEnd)
+		if err == nil {
+			_, err = f.Seek(offset, io.SeekStart)
+		}
+		p.offset = offset
+		if err == nil {
+			p.size = end - offset
+		}
+	case interface{ Len() int }:
+		p.offset = -1
+		p.size = int64(f.Len())
+	default:
+		p.offset = -1
 	}
+	return p
+}
 
-	return e.w.FormDataContentType(), e.b, nil
+func copyFile(w io.Writer, p part) error {
+	_, err := io.Copy(w, p.file)
+	return err
 }
 
-func (e *Encoder) writeField(name string, v interface{}) error {
-	switch val := v.(type) {
-	case *os.File:
-		part, err := e.w.CreateFormFile(name, filepath.Base(val.Name()))
+// writeParts writes parts to w and closes it, copying file contents with
+// copyFn
+func writeParts(w *multipart.Writer, parts []part, copyFn func(io.Writer, part) error) error {
+	for _, p := range parts {
+		if p.file == nil {
+			if err := w.WriteField(p.name, p.value); err != nil {
+				return err
+			}
+			continue
+		}
+		fw, err := w.CreateFormFile(p.name, p.filename)
 		if err != nil {
 			return err
 		}
-		_, err = io.Copy(part, val)
-		return err
-
-	case io.Reader:
-		// It's a file, but we need filename.
-		// For now assume the struct has "File" field and handled specially or
-		// assume we don't know filename.
-		// API usually requires filename for file uploads.
-		// If passed as io.Reader directly, we might default to "file.bin".
-		part, err := e.w.CreateFormFile(name, "file.bin")
-		if err != nil {
+		if err := copyFn(fw, p); err != nil {
 			return err
 		}
-		_, err = io.Copy(part, val)
-		return err
-
-	default:
-		// Primitive
-		return e.w.WriteField(name, fmt.Sprint(val))
 	}
+	return w.Close()
 }
@@ -0,0 +1,210 @@
+package form
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"sync"
+)
+
+// ErrNotRewindable is returned by Stream.Open when a file of the form was
+// already read and cannot seek back to its start
+var ErrNotRewindable = errors.New("form: file cannot be rewound")
+
+// Stream is a multipart/form-data body that is written through an io.Pipe
+// while it is sent, so files are never buffered in memory
+type Stream struct {
+	parts       []part
+	boundary    string
+	contentType string
+	length      int64 // Body length, -1 if unknown
+	fileSize    int64 // Total file bytes, -1 if unknown
+	progress    func(sent, total int64)
+
+	mu      sync.Mutex
+	opened  bool
+	readers []*io.PipeReader // Bodies returned by Open
+}
+
+// NewStream prepares struct v for streaming. Files are read by the bodies
+// returned by Open.
+func NewStream(v interface{}) (*Stream, error) {
+	parts, err := collect(v)
+	if err != nil {
+		return nil, err
+	}
+	w := multipart.NewWriter(nil)
+	s := &Stream{parts: parts, boundary: w.Boundary(), contentType: w.FormDataContentType()}
+	for i, p := range parts {
+		if p.file == nil {
+			continue
+		}
+		if p.offset >= 0 {
+			parts[i].at = readerAt(p.file)
+		}
+		if p.size < 0 {
+			s.fileSize = -1
+			break
+		}
+		s.fileSize += p.size
+	}
+	s.length = s.computeLength()
+	return s, nil
+}
+
+// computeLength encodes the form without file contents to measure the
+// multipart overhead
+func (s *Stream) computeLength() int64 {
+	if s.fileSize < 0 {
+		return -1
+	}
+	var n countWriter
+	w := multipart.NewWriter(&n)
+	w.SetBoundary(s.boundary)
+	if err := writeParts(w, s.parts, func(io.Writer, part) error { return nil }); err != nil {
+		return -1
+	}
+	return int64(n) + s.fileSize
+}
+
+// SetProgress calls fn as file contents are sent with the file bytes sent
+// so far and in total, or -1 if the total is unknown. A rewound body
+// reports from zero again. fn runs on the goroutines writing the bodies, so
+// it must be safe for concurrent use.
+func (s *Stream) SetProgress(fn func(sent, total int64)) {
+	s.progress = fn
+}
+
+// ContentType returns the Content-Type header of the body
+func (s *Stream) ContentType() string {
+	return s.contentType
+}
+
+// ContentLength returns the length of the body, or -1 if a file has an
+// unknown size
+func (s *Stream) ContentLength() int64 {
+	return s.length
+}
+
+// Rewindable reports whether Open can be called more than once, which
+// requires every file to be an io.Seeker
+func (s *Stream) Rewindable() bool {
+	for _, p := range s.parts {
+		if p.file != nil && p.offset < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Open returns a reader of the body. If Rewindable, each call returns an
+// independent body that reads the files through its own section, so
+// callers of http.Request.GetBody, such as signing middleware, leave the
+// body being sent intact. Otherwise calls after the first fail with
+// ErrNotRewindable.
+func (s *Stream) Open() (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := s.parts
+	if s.Rewindable() {
+		parts = s.sections()
+	} else if s.opened {
+		return nil, ErrNotRewindable
+	}
+	s.opened = true
+
+	pr, pw := io.Pipe()
+	s.readers = append(s.readers, pr)
+	go func() {
+		w := multipart.NewWriter(pw)
+		w.SetBoundary(s.boundary)
+		pw.CloseWithError(writeParts(w, parts, s.copyFile()))
+	}()
+	return pr, nil
+}
+
+// Close stops the writers of all opened bodies
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.readers {
+		r.CloseWithError(io.ErrClosedPipe)
+	}
+	s.readers = nil
+	return nil
+}
+
+// sections returns the parts with each file replaced by a reader of its
+// range that does not move the positions of other bodies
+func (s *Stream) sections() []part {
+	parts := append([]part(nil), s.parts...)
+	for i, p := range parts {
+		if p.file != nil {
+			parts[i].file = io.NewSectionReader(p.at, p.offset, p.size)
+		}
+	}
+	return parts
+}
+
+// readerAt reads a seekable file by offset. Files without ReadAt are read by
+// seeking, one read at a time.
+func readerAt(f io.Reader) io.ReaderAt {
+	if ra, ok := f.(io.ReaderAt); ok {
+		return ra
+	}
+	return &seekReaderAt{rs: f.(io.ReadSeeker)}
+}
+
+type seekReaderAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (r *seekReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.rs, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// copyFile returns a copy function that reports progress across all files
+// of one body
+func (s *Stream) copyFile() func(io.Writer, part) error {
+	if s.progress == nil {
+		return copyFile
+	}
+	var sent int64
+	return func(w io.Writer, p part) error {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := p.file.Read(buf)
+			if n > 0 {
+				if _, werr := w.Write(buf[:n]); werr != nil {
+					return werr
+				}
+				sent += int64(n)
+				s.progress(sent, s.fileSize)
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type countWriter int64
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	*c += countWriter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,215 @@
+package form
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+)
+
+func readStream(t *testing.T, s *Stream) string {
+	t.Helper()
+	body, err := s.Open()
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	return string(data)
+}
+
+func TestStream(t *testing.T) {
+	form := TestForm{
+		String: "hello",
+		Int:    42,
+		File:   strings.NewReader("file content"),
+		OptSet: option.Ptr(option.Some("set")),
+	}
+
+	s, err := NewStream(form)
+	if err != nil {
+		t.Fatalf("NewStream error: %v", err)
+	}
+	body := readStream(t, s)
+
+	if int64(len(body)) != s.ContentLength() {
+		t.Errorf("ContentLength() = %d, body is %d bytes", s.ContentLength(), len(body))
+	}
+
+	_, params, err := mime.ParseMediaType(s.ContentType())
+	if err != nil {
+		t.Fatalf("ContentType() = %q: %v", s.ContentType(), err)
+	}
+	r := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	fields := map[string]string{}
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart error: %v", err)
+		}
+		data, _ := io.ReadAll(p)
+		fields[p.FormName()] = string(data)
+	}
+	want := map[string]string{"string": "hello", "int": "42", "file": "file content", "opt_set": "set"}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("field %s = %q, want %q", name, fields[name], value)
+		}
+	}
+	if len(fields) != len(want) {
+		t.Errorf("fields = %v", fields)
+	}
+}
+
+func TestStream_Rewind(t *testing.T) {
+	file := strings.NewReader("skipped file content")
+	file.Seek(8, io.SeekStart)
+
+	s, err := NewStream(TestForm{String: "hello", File: file})
+	if err != nil {
+		t.Fatalf("NewStream error: %v", err)
+	}
+	if !s.Rewindable() {
+		t.Fatal("Rewindable() = false for a strings.Reader")
+	}
+
+	// Abandon the first body partway, as a failed attempt would
+	first, _ := s.Open()
+	io.ReadFull(first, make([]byte, 10))
+
+	second := readStream(t, s)
+	if !strings.Contains(second, "\r\n\r\nfile content\r\n") || strings.Contains(second, "skipped") {
+		t.Errorf("body = %q", second)
+	}
+	if third := readStream(t, s); third != second {
+		t.Errorf("rewound body differs:\n%q\n%q", third, second)
+	}
+}
+
+func TestStream_IndependentBodies(t *testing.T) {
+	tests := []struct {
+		name string
+		file io.Reader
+	}{
+		{"reader at", strings.NewReader("file content")},
+		// Hides ReadAt so the file is read by seeking
+		{"seeker", struct{ io.ReadSeeker }{strings.NewReader("file content")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewStream(TestForm{String: "hello", File: tt.file})
+			if err != nil {
+				t.Fatalf("NewStream error: %v", err)
+			}
+
+			// A second body, as GetBody returns, must not disturb the first
+			first, _ := s.Open()
+			head := make([]byte, 10)
+			io.ReadFull(first, head)
+
+			second := readStream(t, s)
+			rest, err := io.ReadAll(first)
+			if err != nil {
+				t.Fatalf("first body error after Open: %v", err)
+			}
+			if got := string(head) + string(rest); got != second {
+				t.Errorf("bodies differ:\n%q\n%q", got, second)
+			}
+			if !strings.Contains(second, "\r\n\r\nfile content\r\n") {
+				t.Errorf("body = %q", second)
+			}
+		})
+	}
+}
+
+func TestStream_NotRewindable(t *testing.T) {
+	s, err := NewStream(TestForm{File: io.MultiReader(strings.NewReader("content"))})
+	if err != nil {
+		t.Fatalf("NewStream error: %v", err)
+	}
+	if s.Rewindable() || s.ContentLength() != -1 {
+		t.Errorf("Rewindable() = %v, ContentLength() = %d", s.Rewindable(), s.ContentLength())
+	}
+
+	if body := readStream(t, s); !strings.Contains(body, "content") {
+		t.Errorf("body = %q", body)
+	}
+	if _, err := s.Open(); !errors.Is(err, ErrNotRewindable) {
+		t.Errorf("second Open error = %v, want ErrNotRewindable", err)
+	}
+}
+
+func TestStream_Progress(t *testing.T) {
+	type MultiFileForm struct {
+		File1 io.Reader `json:"file1"`
+		File2 io.Reader `json:"file2"`
+	}
+
+	tests := []struct {
+		name      string
+		form      MultiFileForm
+		wantSent  int64
+		wantTotal int64
+	}{
+		{
+			name:      "known sizes",
+			form:      MultiFileForm{File1: strings.NewReader("abc"), File2: bytes.NewBufferString("defg")},
+			wantSent:  7,
+			wantTotal: 7,
+		},
+		{
+			name:      "unknown size",
+			form:      MultiFileForm{File1: strings.NewReader("abc"), File2: io.MultiReader(strings.NewReader("defg"))},
+			wantSent:  7,
+			wantTotal: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewStream(tt.form)
+			if err != nil {
+				t.Fatalf("NewStream error: %v", err)
+			}
+			var sent, total int64
+			s.SetProgress(func(n, size int64) {
+				if n < sent {
+					t.Errorf("progress went back from %d to %d", sent, n)
+				}
+				sent, total = n, size
+			})
+			readStream(t, s)
+			if sent != tt.wantSent || total != tt.wantTotal {
+				t.Errorf("progress = %d/%d, want %d/%d", sent, total, tt.wantSent, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestStream_SkipsNilFile(t *testing.T) {
+	s, err := NewStream(TestForm{String: "hello"})
+	if err != nil {
+		t.Fatalf("NewStream error: %v", err)
+	}
+	if body := readStream(t, s); strings.Contains(body, `name="file"`) {
+		t.Errorf("nil file was encoded: %q", body)
+	}
+}
+
+func TestStream_ExpectedStruct(t *testing.T) {
+	if _, err := NewStream("not a struct"); err == nil {
+		t.Error("expected error for non-struct")
+	}
+}
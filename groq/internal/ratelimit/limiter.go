@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token-bucket limiter for requests per minute and tokens per
+// minute. It is safe for concurrent use and adapts to the remaining budget
+// reported by the server through Observe.
+type Limiter struct {
+	mu       sync.Mutex
+	requests bucket
+	tokens   bucket
+
+	// pausedUntil blocks all callers, e.g. after a 429 with retry-after
+	pausedUntil time.Time
+
+	now func() time.Time
+}
+
+// bucket refills continuously at capacity per minute. A zero capacity means
+// unlimited.
+type bucket struct {
+	capacity  float64
+	available float64
+	last      time.Time
+}
+
+func (b *bucket) refill(now time.Time) {
+	if b.capacity == 0 {
+		return
+	}
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.available += elapsed * b.capacity / 60
+		if b.available > b.capacity {
+			b.available = b.capacity
+		}
+	}
+	b.last = now
+}
+
+// wait returns how long until n units are available
+func (b *bucket) wait(n float64) time.Duration {
+	if b.capacity == 0 || b.available >= n {
+		return 0
+	}
+	missing := n - b.available
+	return time.Duration(missing / (b.capacity / 60) * float64(time.Second))
+}
+
+// clamp limits n to the bucket capacity so oversized requests can proceed
+// once the bucket is full instead of waiting forever
+func (b *bucket) clamp(n float64) float64 {
+	if b.capacity > 0 && n > b.capacity {
+		return b.capacity
+	}
+	return n
+}
+
+// New creates a Limiter allowing rpm requests and tpm tokens per minute.
+// A zero value disables the corresponding limit.
+func New(rpm, tpm int) *Limiter {
+	now := time.Now()
+	return &Limiter{
+		requests: bucket{capacity: float64(rpm), available: float64(rpm), last: now},
+		tokens:   bucket{capacity: float64(tpm), available: float64(tpm), last: now},
+		now:      time.Now,
+	}
+}
+
+// Wait blocks until one request and the given number of tokens are
+// available, then reserves them. It returns the context error if ctx is done
+// first.
+func (l *Limiter) Wait(ctx context.Context, tokens int) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		l.requests.refill(now)
+		l.tokens.refill(now)
+
+		needTokens := l.tokens.clamp(float64(tokens))
+		delay := l.requests.wait(1)
+		if d := l.tokens.wait(needTokens); d > delay {
+			delay = d
+		}
+		if d := l.pausedUntil.Sub(now); d > delay {
+			delay = d
+		}
+
+		if delay <= 0 {
+			if l.requests.capacity > 0 {
+				l.requests.available--
+			}
+			if l.tokens.capacity > 0 {
+				l.tokens.available -= needTokens
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// State is the rate limit state reported by the server. Fields with a false
+// Has* flag were not present in the response.
+type State struct {
+	HasRequests       bool
+	RemainingRequests int
+	ResetRequests     time.Duration
+
+	HasTokens       bool
+	RemainingTokens int
+	ResetTokens     time.Duration
+
+	RetryAfter time.Duration
+}
+
+// Observe adjusts the limiter to the budget reported by the server. The
+// local estimate is lowered when the server reports less remaining budget,
+// and all callers are paused when the server asks to retry later or the
+// budget is exhausted.
+func (l *Limiter) Observe(s State) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.requests.refill(now)
+	l.tokens.refill(now)
+
+	if s.HasRequests && l.requests.capacity > 0 && float64(s.RemainingRequests) < l.requests.available {
+		l.requests.available = float64(s.RemainingRequests)
+	}
+	if s.HasTokens && l.tokens.capacity > 0 && float64(s.RemainingTokens) < l.tokens.available {
+		l.tokens.available = float64(s.RemainingTokens)
+	}
+
+	pause := s.RetryAfter
+	if s.HasRequests && s.RemainingRequests <= 0 && s.ResetRequests > pause {
+		pause = s.ResetRequests
+	}
+	if s.HasTokens && s.RemainingTokens <= 0 && s.ResetTokens > pause {
+		pause = s.ResetTokens
+	}
+	if until := now.Add(pause); pause > 0 && until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
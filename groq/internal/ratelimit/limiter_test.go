@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock lets tests advance time without sleeping
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+func newTestLimiter(rpm, tpm int) (*Limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := New(rpm, tpm)
+	l.now = clock.now
+	l.requests.last = clock.t
+	l.tokens.last = clock.t
+	return l, clock
+}
+
+func TestLimiter_AllowsWithinBudget(t *testing.T) {
+	l, _ := newTestLimiter(2, 1000)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx, 400); err != nil {
+			t.Fatalf("Wait() #%d error = %v", i, err)
+		}
+	}
+	if l.requests.available != 0 {
+		t.Errorf("requests available = %v, want 0", l.requests.available)
+	}
+	if l.tokens.available != 200 {
+		t.Errorf("tokens available = %v, want 200", l.tokens.available)
+	}
+}
+
+func TestLimiter_BlocksWhenExhausted(t *testing.T) {
+	l, _ := newTestLimiter(1, 0)
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := l.Wait(ctx, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestLimiter_Refill(t *testing.T) {
+	l, clock := newTestLimiter(60, 600)
+	l.requests.available = 0
+	l.tokens.available = 0
+
+	clock.advance(time.Second)
+	l.requests.refill(clock.now())
+	l.tokens.refill(clock.now())
+
+	if l.requests.available != 1 {
+		t.Errorf("requests available = %v, want 1", l.requests.available)
+	}
+	if l.tokens.available != 10 {
+		t.Errorf("tokens available = %v, want 10", l.tokens.available)
+	}
+
+	clock.advance(time.Hour)
+	l.tokens.refill(clock.now())
+	if l.tokens.available != 600 {
+		t.Errorf("tokens available = %v, want capacity 600", l.tokens.available)
+	}
+}
+
+func TestLimiter_OversizedRequest(t *testing.T) {
+	l, _ := newTestLimiter(0, 100)
+
+	if err := l.Wait(context.Background(), 1000); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if l.tokens.available != 0 {
+		t.Errorf("tokens available = %v, want 0", l.tokens.available)
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l, _ := newTestLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(context.Background(), 1_000_000); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+}
+
+func TestLimiter_Observe(t *testing.T) {
+	l, _ := newTestLimiter(100, 10000)
+
+	l.Observe(State{HasTokens: true, RemainingTokens: 500})
+	if l.tokens.available != 500 {
+		t.Errorf("tokens available = %v, want 500", l.tokens.available)
+	}
+
+	// Higher remaining budget than the local estimate is ignored
+	l.Observe(State{HasTokens: true, RemainingTokens: 9000})
+	if l.tokens.available != 500 {
+		t.Errorf("tokens available = %v, want 500", l.tokens.available)
+	}
+
+	// Missing headers leave the estimate alone
+	l.Observe(State{})
+	if l.requests.available != 100 {
+		t.Errorf("requests available = %v, want 100", l.requests.available)
+	}
+}
+
+func TestLimiter_ObservePause(t *testing.T) {
+	l, clock := newTestLimiter(0, 0)
+
+	l.Observe(State{RetryAfter: 2 * time.Second})
+	if want := clock.now().Add(2 * time.Second); !l.pausedUntil.Equal(want) {
+		t.Errorf("pausedUntil = %v, want %v", l.pausedUntil, want)
+	}
+
+	l.Observe(State{HasTokens: true, RemainingTokens: 0, ResetTokens: 5 * time.Second})
+	if want := clock.now().Add(5 * time.Second); !l.pausedUntil.Equal(want) {
+		t.Errorf("pausedUntil = %v, want %v", l.pausedUntil, want)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() while paused error = %v, want context.DeadlineExceeded", err)
+	}
+
+	clock.advance(5 * time.Second)
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Errorf("Wait() after pause error = %v", err)
+	}
+}
@@ -2,10 +2,15 @@ package retry
 
 import (
 	"context"
+	"errors"
+	"io"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
@@ -17,6 +22,10 @@ const (
 type Config struct {
 	MaxRetries  int
 	ShouldRetry func(*http.Response) bool
+
+	// ShouldRetryError decides whether a transport error returned by fn
+	// should be retried. If nil, errors are returned immediately.
+	ShouldRetryError func(error) bool
 }
 
 func DefaultShouldRetry(resp *http.Response) bool {
@@ -34,25 +43,97 @@ func DefaultShouldRetry(resp *http.Response) bool {
 	return resp.StatusCode >= 500
 }
 
-func CalculateBackoff(attempt int, resp *http.Response) time.Duration {
-	// Check Retry-After header
-	if retryAfter := resp.Header.Get("retry-after"); retryAfter != "" {
-		// Try parsing as seconds
-		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
-			return time.Duration(seconds) * time.Second
+// DefaultShouldRetryError reports whether err is a transient transport error
+// that is safe to retry: timeouts, connection resets, dropped connections,
+// temporary DNS failures and HTTP/2 GOAWAY frames. Context cancellation is
+// never retried.
+func DefaultShouldRetryError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Connection dropped mid-response
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	// Connection-level syscall failures
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	// Failures to establish a connection (dial timeouts, unreachable hosts)
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	// TLS handshake timeouts and client/dial timeouts implement net.Error
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// net/http bundles its HTTP/2 implementation, so GOAWAY and idle
+	// connection errors are only distinguishable by their message.
+	msg := err.Error()
+	for _, s := range transientMessages {
+		if strings.Contains(msg, s) {
+			return true
 		}
-		// Try parsing as HTTP-date
-		if t, err := http.ParseTime(retryAfter); err == nil {
-			if d := time.Until(t); d > 0 {
-				return d
+	}
+
+	return false
+}
+
+var transientMessages = []string{
+	"GOAWAY",
+	"TLS handshake timeout",
+	"server closed idle connection",
+}
+
+// IsTimeout reports whether err represents a timeout, either a network
+// timeout or an expired context deadline.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func CalculateBackoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		// Check Retry-After header
+		if retryAfter := resp.Header.Get("retry-after"); retryAfter != "" {
+			// Try parsing as seconds
+			if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+			// Try parsing as HTTP-date
+			if t, err := http.ParseTime(retryAfter); err == nil {
+				if d := time.Until(t); d > 0 {
+					return d
+				}
 			}
 		}
-	}
 
-	// Check retry-after-ms header (non-standard)
-	if retryMs := resp.Header.Get("retry-after-ms"); retryMs != "" {
-		if ms, err := strconv.Atoi(retryMs); err == nil {
-			return time.Duration(ms) * time.Millisecond
+		// Check retry-after-ms header (non-standard)
+		if retryMs := resp.Header.Get("retry-after-ms"); retryMs != "" {
+			if ms, err := strconv.Atoi(retryMs); err == nil {
+				return time.Duration(ms) * time.Millisecond
+			}
 		}
 	}
 
@@ -62,6 +143,11 @@ func CalculateBackoff(attempt int, resp *http.Response) time.Duration {
 	return time.Duration(delay * jitter)
 }
 
+// Do calls fn until it succeeds, returns a non-retryable result, or
+// MaxRetries is exhausted. Responses are retried according to ShouldRetry
+// and transport errors according to ShouldRetryError; both use the backoff
+// from CalculateBackoff. When retries are exhausted the last response or
+// error is returned.
 func Do(ctx context.Context, cfg Config, fn func() (*http.Response, error)) (*http.Response, error) {
 	var resp *http.Response
 	var err error
@@ -70,32 +156,26 @@ func Do(ctx context.Context, cfg Config, fn func() (*http.Response, error)) (*ht
 		resp, err = fn()
 
 		if err != nil {
-			// Network errors (err != nil) are typically retried if safe/idempotent?
-			// For now we don't retry network errors automatically unless we inspect them
-			// But usually SDKs retry on connection errors.
-			// The plan doesn't specify handling network error retries explicitly in the code snippet,
-			// but logic implies we return if error.
-			// Let's stick to the plan's snippet which returns err if not nil.
-			// Wait, the plan snippet says:
-			// if err != nil || !cfg.ShouldRetry(resp) { return resp, err }
-			// This means it does NOT retry on error? That seems wrong for a robust SDK.
-			// However, checking the snippet in 3.4:
-			// if err != nil || !cfg.ShouldRetry(resp) { return resp, err }
-			// This implies only status code retries.
-			// But `DefaultShouldRetry` takes `resp`.
-			// If `err` is not nil, `resp` is nil.
-			// So we return `nil, err`.
-			// If we want to retry on timeout/connection error, we need to handle `err`.
-			// I will stick to the plan's snippet for now.
-			return resp, err
-		}
-
-		if !cfg.ShouldRetry(resp) {
+			// A cancelled or expired context is final, whatever the error says
+			if ctx.Err() != nil {
+				return resp, err
+			}
+			if cfg.ShouldRetryError == nil || !cfg.ShouldRetryError(err) {
+				return resp, err
+			}
+		} else if !cfg.ShouldRetry(resp) {
 			return resp, err
 		}
 
 		if attempt < cfg.MaxRetries {
 			backoff := CalculateBackoff(attempt, resp)
+
+			// Release the connection of a response we are about to discard
+			if resp != nil && resp.Body != nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+
 			select {
 			case <-time.After(backoff):
 			case <-ctx.Done():
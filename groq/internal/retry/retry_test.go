@@ -3,8 +3,12 @@ package retry
 import (
 	"context"
 	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -238,3 +242,101 @@ func TestCalculateBackoff_InvalidRetryAfter(t *testing.T) {
 		t.Errorf("CalculateBackoff with invalid Retry-After = %v, want exponential backoff", delay)
 	}
 }
+
+func TestDefaultShouldRetryError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"context canceled", &url.Error{Op: "Post", URL: "http://test", Err: context.Canceled}, false},
+		{"unexpected EOF", &url.Error{Op: "Post", URL: "http://test", Err: io.ErrUnexpectedEOF}, true},
+		{"connection reset", &url.Error{Op: "Post", URL: "http://test", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, true},
+		{"dial error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no route to host")}, true},
+		{"dns temporary", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, true},
+		{"dns not found", &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{"timeout", &timeoutError{}, true},
+		{"goaway", errors.New("http2: server sent GOAWAY and closed the connection"), true},
+		{"tls handshake timeout", errors.New("net/http: TLS handshake timeout"), true},
+		{"generic", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultShouldRetryError(tt.err); got != tt.want {
+				t.Errorf("DefaultShouldRetryError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	if !IsTimeout(&timeoutError{}) {
+		t.Error("expected net.Error timeout to be a timeout")
+	}
+	if !IsTimeout(&url.Error{Op: "Get", URL: "http://test", Err: context.DeadlineExceeded}) {
+		t.Error("expected context.DeadlineExceeded to be a timeout")
+	}
+	if IsTimeout(io.ErrUnexpectedEOF) {
+		t.Error("expected io.ErrUnexpectedEOF not to be a timeout")
+	}
+}
+
+func TestDo_RetryNetworkError(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+
+	cfg := Config{
+		MaxRetries:       3,
+		ShouldRetry:      DefaultShouldRetry,
+		ShouldRetryError: DefaultShouldRetryError,
+	}
+
+	resp, err := Do(ctx, cfg, func() (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, &url.Error{Op: "Post", URL: "http://test", Err: io.ErrUnexpectedEOF}
+		}
+		return &http.Response{StatusCode: 200}, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestDo_NetworkErrorRetriesExhausted(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+
+	cfg := Config{
+		MaxRetries:       1,
+		ShouldRetry:      DefaultShouldRetry,
+		ShouldRetryError: DefaultShouldRetryError,
+	}
+
+	_, err := Do(ctx, cfg, func() (*http.Response, error) {
+		attempts++
+		return nil, &url.Error{Op: "Post", URL: "http://test", Err: io.ErrUnexpectedEOF}
+	})
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
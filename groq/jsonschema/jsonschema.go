@@ -0,0 +1,357 @@
+// Package jsonschema generates JSON Schemas from Go types for tool
+// parameters and structured outputs.
+//
+// Schemas follow the rules of strict mode: every object lists all of its
+// properties as required and sets additionalProperties to false. Fields that
+// may be absent (pointers, or fields tagged omitempty) are required but
+// nullable instead. Maps are not supported, since strict mode requires every
+// object to list its properties; use a struct or a slice of key-value structs.
+//
+// Field names come from json tags. Constraints come from these struct tags:
+//
+//	description:"City name"   description of the field
+//	enum:"celsius,fahrenheit" allowed values, comma separated
+//	minimum:"0" maximum:"100" numeric bounds
+//	minLength:"1" maxLength:"64" pattern:"^[a-z]+$" format:"email"
+//	minItems:"1" maxItems:"10"
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema document
+type Schema = map[string]interface{}
+
+// For generates the schema of T
+func For[T any]() (Schema, error) {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect generates the schema of a Go type
+func Reflect(t reflect.Type) (Schema, error) {
+	g := &generator{
+		root:      t,
+		visiting:  make(map[reflect.Type]bool),
+		recursive: make(map[reflect.Type]bool),
+		defs:      make(map[string]interface{}),
+	}
+	schema, err := g.schema(t)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	return schema, nil
+}
+
+type generator struct {
+	root      reflect.Type
+	visiting  map[reflect.Type]bool // Structs being generated, to detect cycles
+	recursive map[reflect.Type]bool // Structs referenced from within themselves
+	defs      map[string]interface{}
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (g *generator) schema(t reflect.Type) (Schema, error) {
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}, nil
+	case rawJSONType:
+		return Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(s), nil
+	case reflect.Bool:
+		return Schema{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Schema{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}, nil
+	case reflect.String:
+		return Schema{"type": "string"}, nil
+	case reflect.Interface:
+		return Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// encoding/json writes []byte as base64
+			return Schema{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := Schema{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			s["minItems"] = t.Len()
+			s["maxItems"] = t.Len()
+		}
+		return s, nil
+	case reflect.Map:
+		return nil, fmt.Errorf("jsonschema: unsupported type %s: strict mode requires objects without additional properties", t)
+	case reflect.Struct:
+		return g.structSchema(t)
+	}
+	return nil, fmt.Errorf("jsonschema: unsupported type %s", t)
+}
+
+func (g *generator) structSchema(t reflect.Type) (Schema, error) {
+	if g.visiting[t] {
+		g.recursive[t] = true
+		return g.ref(t), nil
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	properties := Schema{}
+	required := []string{}
+	if err := g.fields(t, properties, &required); err != nil {
+		return nil, err
+	}
+
+	s := Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	if g.recursive[t] && t != g.root {
+		g.defs[defName(t)] = s
+		return g.ref(t), nil
+	}
+	return s, nil
+}
+
+// field is a struct field found while flattening embedded structs
+type field struct {
+	reflect.StructField
+	name   string // JSON name
+	opts   string // Options of the json tag
+	depth  int    // Embedding depth
+	tagged bool   // The name comes from a json tag
+}
+
+// fields adds the properties of t, flattening embedded structs as
+// encoding/json does
+func (g *generator) fields(t reflect.Type, properties Schema, required *[]string) error {
+	var all []field
+	collectFields(t, 0, map[reflect.Type]bool{}, &all)
+
+	for _, f := range dominantFields(all) {
+		// Constraints apply to the value; null is added afterwards
+		ft := f.Type
+		optional := hasOption(f.opts, "omitempty") || hasOption(f.opts, "omitzero")
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+			optional = true
+		}
+		s, err := g.schema(ft)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if hasOption(f.opts, "string") {
+			s = Schema{"type": "string"}
+		}
+		if err := applyTags(s, f.Tag); err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if optional {
+			s = nullable(s)
+		}
+
+		*required = append(*required, f.name)
+		properties[f.name] = s
+	}
+	return nil
+}
+
+// collectFields appends the fields of t and of its untagged embedded
+// structs in declaration order
+func collectFields(t reflect.Type, depth int, embedding map[reflect.Type]bool, out *[]field) {
+	embedding[t] = true
+	defer delete(embedding, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if !embedding[ft] {
+					collectFields(ft, depth+1, embedding, out)
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = f.Name
+		}
+		*out = append(*out, field{StructField: f, name: name, opts: opts, depth: depth, tagged: tagged})
+	}
+}
+
+// dominantFields resolves fields sharing a JSON name like encoding/json: the
+// shallowest field wins, then a tagged one, and names that stay ambiguous
+// are dropped
+func dominantFields(all []field) []field {
+	byName := make(map[string][]int)
+	for i, f := range all {
+		byName[f.name] = append(byName[f.name], i)
+	}
+
+	var out []field
+	for i, f := range all {
+		if winner, ok := dominantField(all, byName[f.name]); ok && winner == i {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func dominantField(all []field, candidates []int) (int, bool) {
+	depth := all[candidates[0]].depth
+	for _, i := range candidates[1:] {
+		depth = min(depth, all[i].depth)
+	}
+
+	winner, tagged := -1, false
+	ambiguous := false
+	for _, i := range candidates {
+		f := all[i]
+		switch {
+		case f.depth != depth:
+		case winner < 0:
+			winner, tagged = i, f.tagged
+		case f.tagged && !tagged:
+			winner, tagged, ambiguous = i, true, false
+		case f.tagged == tagged:
+			ambiguous = true
+		}
+	}
+	return winner, !ambiguous
+}
+
+// applyTags adds the constraints from struct tags to s
+func applyTags(s Schema, tag reflect.StructTag) error {
+	if d := tag.Get("description"); d != "" {
+		s["description"] = d
+	}
+	if e := tag.Get("enum"); e != "" {
+		values := strings.Split(e, ",")
+		enum := make([]interface{}, len(values))
+		for i, v := range values {
+			v = strings.TrimSpace(v)
+			switch s["type"] {
+			case "integer":
+				n, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid enum value %q: %w", v, err)
+				}
+				enum[i] = n
+			case "number":
+				n, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					return fmt.Errorf("invalid enum value %q: %w", v, err)
+				}
+				enum[i] = n
+			default:
+				enum[i] = v
+			}
+		}
+		s["enum"] = enum
+	}
+	for _, key := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"} {
+		if v := tag.Get(key); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", key, v, err)
+			}
+			s[key] = n
+		}
+	}
+	for _, key := range []string{"minLength", "maxLength", "minItems", "maxItems"} {
+		if v := tag.Get(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", key, v, err)
+			}
+			s[key] = n
+		}
+	}
+	for _, key := range []string{"pattern", "format"} {
+		if v := tag.Get(key); v != "" {
+			s[key] = v
+		}
+	}
+	return nil
+}
+
+// nullable allows null in addition to the values s accepts
+func nullable(s Schema) Schema {
+	switch typ := s["type"].(type) {
+	case string:
+		s["type"] = []interface{}{typ, "null"}
+		if enum, ok := s["enum"].([]interface{}); ok {
+			s["enum"] = append(enum, nil)
+		}
+		return s
+	case []interface{}:
+		return s
+	}
+	if len(s) == 0 {
+		return s // Already accepts anything
+	}
+	return Schema{"anyOf": []interface{}{s, Schema{"type": "null"}}}
+}
+
+func (g *generator) ref(t reflect.Type) Schema {
+	if t == g.root {
+		return Schema{"$ref": "#"}
+	}
+	return Schema{"$ref": "#/$defs/" + defName(t)}
+}
+
+func defName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return strings.NewReplacer(" ", "", "{", "_", "}", "_", ";", "_").Replace(t.String())
+}
+
+func hasOption(opts, name string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type weatherArgs struct {
+	City     string   `json:"city" description:"City name" minLength:"1"`
+	Unit     string   `json:"unit" enum:"celsius,fahrenheit"`
+	Days     int      `json:"days,omitempty" minimum:"1" maximum:"14"`
+	Details  *details `json:"details"`
+	internal string
+	Ignored  string `json:"-"`
+}
+
+type details struct {
+	Hourly bool     `json:"hourly"`
+	Fields []string `json:"fields" maxItems:"5"`
+}
+
+func TestFor_Struct(t *testing.T) {
+	got, err := For[weatherArgs]()
+	if err != nil {
+		t.Fatalf("For error: %v", err)
+	}
+
+	want := `{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["city", "unit", "days", "details"],
+		"properties": {
+			"city": {"type": "string", "description": "City name", "minLength": 1},
+			"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+			"days": {"type": ["integer", "null"], "minimum": 1, "maximum": 14},
+			"details": {
+				"type": ["object", "null"],
+				"additionalProperties": false,
+				"required": ["hourly", "fields"],
+				"properties": {
+					"hourly": {"type": "boolean"},
+					"fields": {"type": "array", "items": {"type": "string"}, "maxItems": 5}
+				}
+			}
+		}
+	}`
+	assertJSONEqual(t, got, want)
+}
+
+func TestFor_Types(t *testing.T) {
+	type embedded struct {
+		ID string `json:"id"`
+	}
+	type all struct {
+		embedded
+		When    time.Time       `json:"when"`
+		Raw     json.RawMessage `json:"raw"`
+		Any     interface{}     `json:"any"`
+		Bytes   []byte          `json:"bytes"`
+		Pair    [2]float64      `json:"pair"`
+		Count   int64           `json:"count,string"`
+		Level   int             `json:"level" enum:"1,2,3"`
+		Mode    *string         `json:"mode" enum:"a,b"`
+		NoTag   bool
+		Renamed bool `json:"renamed_field"`
+	}
+
+	got, err := For[all]()
+	if err != nil {
+		t.Fatalf("For error: %v", err)
+	}
+	props := got["properties"].(Schema)
+
+	checks := map[string]string{
+		"id":            `{"type":"string"}`,
+		"when":          `{"type":"string","format":"date-time"}`,
+		"raw":           `{}`,
+		"any":           `{}`,
+		"bytes":         `{"type":"string","contentEncoding":"base64"}`,
+		"pair":          `{"type":"array","items":{"type":"number"},"minItems":2,"maxItems":2}`,
+		"count":         `{"type":"string"}`,
+		"level":         `{"type":"integer","enum":[1,2,3]}`,
+		"mode":          `{"type":["string","null"],"enum":["a","b",null]}`,
+		"NoTag":         `{"type":"boolean"}`,
+		"renamed_field": `{"type":"boolean"}`,
+	}
+	for name, want := range checks {
+		s, ok := props[name]
+		if !ok {
+			t.Errorf("missing property %s", name)
+			continue
+		}
+		assertJSONEqual(t, s, want)
+	}
+	if len(props) != len(checks) {
+		t.Errorf("got %d properties, want %d", len(props), len(checks))
+	}
+}
+
+type Base struct {
+	ID    string `json:"id" description:"base"`
+	Name  string `json:"name"`
+	Label string
+	Kind  string `json:"kind"`
+}
+
+type Extra struct {
+	Kind  string `json:"kind"`
+	Label string `json:"Label"`
+}
+
+type shadowed struct {
+	Base
+	*Extra
+	ID string `json:"id" description:"outer"`
+}
+
+func TestFor_EmbeddedDominance(t *testing.T) {
+	got, err := For[shadowed]()
+	if err != nil {
+		t.Fatalf("For error: %v", err)
+	}
+	props := got["properties"].(Schema)
+
+	// The outer id wins by depth, the tagged Extra.Label beats the untagged
+	// Base.Label, and kind is ambiguous at the same depth, as in encoding/json
+	assertJSONEqual(t, props["id"], `{"type":"string","description":"outer"}`)
+	assertJSONEqual(t, got["required"], `["name","Label","id"]`)
+	if _, ok := props["kind"]; ok {
+		t.Error("ambiguous field kind was included")
+	}
+
+	data, err := json.Marshal(shadowed{Base: Base{ID: "inner", Name: "n", Label: "a", Kind: "k"}, Extra: &Extra{Kind: "k", Label: "b"}, ID: "outer"})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if err := ValidateJSON(got, data); err != nil {
+		t.Errorf("json.Marshal output %s fails the schema: %v", data, err)
+	}
+}
+
+type node struct {
+	Value    string  `json:"value"`
+	Children []*node `json:"children"`
+}
+
+type tree struct {
+	Root node `json:"root"`
+}
+
+func TestFor_Recursive(t *testing.T) {
+	got, err := For[node]()
+	if err != nil {
+		t.Fatalf("For error: %v", err)
+	}
+	items := got["properties"].(Schema)["children"].(Schema)["items"]
+	assertJSONEqual(t, items, `{"anyOf":[{"$ref":"#"},{"type":"null"}]}`)
+
+	got, err = For[tree]()
+	if err != nil {
+		t.Fatalf("For error: %v", err)
+	}
+	assertJSONEqual(t, got["properties"].(Schema)["root"], `{"$ref":"#/$defs/node"}`)
+	defs, ok := got["$defs"].(map[string]interface{})
+	if !ok || defs["node"] == nil {
+		t.Fatalf("$defs = %v, want node definition", got["$defs"])
+	}
+}
+
+func TestReflect_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want string
+	}{
+		{"channel", reflect.TypeOf(make(chan int)), "unsupported type chan int"},
+		{"func field", reflect.TypeOf(struct{ F func() }{}), "field F: jsonschema: unsupported type func()"},
+		{"map", reflect.TypeOf(map[string]int{}), "unsupported type map[string]int"},
+		{"map field", reflect.TypeOf(struct {
+			Labels map[string]string `json:"labels"`
+		}{}), "field Labels: jsonschema: unsupported type map[string]string"},
+		{"bad minimum", reflect.TypeOf(struct {
+			N int `minimum:"low"`
+		}{}), `invalid minimum "low"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Reflect(tt.typ)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got interface{}, want string) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var g, w interface{}
+	json.Unmarshal(gotJSON, &g)
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid want JSON: %v", err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("schema = %s\nwant %s", gotJSON, want)
+	}
+}
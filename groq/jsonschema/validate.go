@@ -0,0 +1,311 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError reports where a value violates a schema
+type ValidationError struct {
+	Path    string // JSON path of the value, e.g. "$.items[0].name"
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("jsonschema: %s: %s", e.Path, e.Message)
+}
+
+// Validate checks a decoded JSON value (as produced by json.Unmarshal into
+// an interface{}) against schema. It supports the keywords produced by this
+// package: type, enum, properties, required, additionalProperties, items,
+// anyOf, $ref, and the numeric, string and array bounds.
+func Validate(schema Schema, value interface{}) error {
+	v := &validator{root: schema}
+	return v.validate(schema, value, "$")
+}
+
+// ValidateJSON decodes data and validates it against schema
+func ValidateJSON(schema Schema, data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return Validate(schema, value)
+}
+
+type validator struct {
+	root Schema
+}
+
+func (v *validator) validate(s Schema, value interface{}, path string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		target, err := v.resolve(ref)
+		if err != nil {
+			return &ValidationError{Path: path, Message: err.Error()}
+		}
+		return v.validate(target, value, path)
+	}
+
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		var firstErr error
+		for _, option := range anyOf {
+			sub, _ := option.(Schema)
+			err := v.validate(sub, value, path)
+			if err == nil {
+				firstErr = nil
+				break
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+	}
+
+	if t, ok := s["type"]; ok && !matchesType(t, value) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("expected %s, got %s", typeString(t), jsonType(value))}
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if equalJSON(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("value %s is not one of the allowed values", compact(value))}
+		}
+	}
+
+	switch val := value.(type) {
+	case string:
+		return v.validateString(s, val, path)
+	case float64:
+		return v.validateNumber(s, val, path)
+	case []interface{}:
+		return v.validateArray(s, val, path)
+	case map[string]interface{}:
+		return v.validateObject(s, val, path)
+	}
+	return nil
+}
+
+func (v *validator) validateString(s Schema, val string, path string) error {
+	n := utf8.RuneCountInString(val)
+	if min, ok := number(s["minLength"]); ok && float64(n) < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("length %d is less than %v", n, min)}
+	}
+	if max, ok := number(s["maxLength"]); ok && float64(n) > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("length %d is greater than %v", n, max)}
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("invalid pattern %q", pattern)}
+		}
+		if !re.MatchString(val) {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%q does not match pattern %q", val, pattern)}
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateNumber(s Schema, val float64, path string) error {
+	if min, ok := number(s["minimum"]); ok && val < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v is less than minimum %v", val, min)}
+	}
+	if max, ok := number(s["maximum"]); ok && val > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v is greater than maximum %v", val, max)}
+	}
+	if min, ok := number(s["exclusiveMinimum"]); ok && val <= min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v is not greater than %v", val, min)}
+	}
+	if max, ok := number(s["exclusiveMaximum"]); ok && val >= max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v is not less than %v", val, max)}
+	}
+	if m, ok := number(s["multipleOf"]); ok && m != 0 {
+		if q := val / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%v is not a multiple of %v", val, m)}
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateArray(s Schema, val []interface{}, path string) error {
+	if min, ok := number(s["minItems"]); ok && float64(len(val)) < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%d items, want at least %v", len(val), min)}
+	}
+	if max, ok := number(s["maxItems"]); ok && float64(len(val)) > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%d items, want at most %v", len(val), max)}
+	}
+	if items, ok := s["items"].(Schema); ok {
+		for i, item := range val {
+			if err := v.validate(items, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateObject(s Schema, val map[string]interface{}, path string) error {
+	for _, name := range stringList(s["required"]) {
+		if _, ok := val[name]; !ok {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("missing required property %q", name)}
+		}
+	}
+
+	properties, _ := s["properties"].(Schema)
+	keys := make([]string, 0, len(val))
+	for k := range val {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		propPath := path + "." + k
+		if prop, ok := properties[k].(Schema); ok {
+			if err := v.validate(prop, val[k], propPath); err != nil {
+				return err
+			}
+			continue
+		}
+		switch extra := s["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				return &ValidationError{Path: path, Message: fmt.Sprintf("unexpected property %q", k)}
+			}
+		case Schema:
+			if err := v.validate(extra, val[k], propPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) resolve(ref string) (Schema, error) {
+	if ref == "#" {
+		return v.root, nil
+	}
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	defs, _ := v.root["$defs"].(map[string]interface{})
+	def, ok := defs[name].(Schema)
+	if !ok {
+		return nil, fmt.Errorf("undefined $ref %q", ref)
+	}
+	return def, nil
+}
+
+func matchesType(t interface{}, value interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return matchesTypeName(t, value)
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok && matchesTypeName(s, value) {
+				return true
+			}
+		}
+		return false
+	case []string:
+		for _, name := range t {
+			if matchesTypeName(name, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesTypeName(name string, value interface{}) bool {
+	switch name {
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	}
+	return jsonType(value) == name
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func typeString(t interface{}) string {
+	if names, ok := t.([]interface{}); ok {
+		parts := make([]string, len(names))
+		for i, n := range names {
+			parts[i] = fmt.Sprint(n)
+		}
+		return strings.Join(parts, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func stringList(v interface{}) []string {
+	switch l := v.(type) {
+	case []string:
+		return l
+	case []interface{}:
+		out := make([]string, 0, len(l))
+		for _, s := range l {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func equalJSON(a, b interface{}) bool {
+	return compact(a) == compact(b)
+}
+
+func compact(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
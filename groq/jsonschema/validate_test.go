@@ -0,0 +1,61 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type order struct {
+	ID       string   `json:"id" pattern:"^ord_"`
+	Quantity int      `json:"quantity" minimum:"1" maximum:"10"`
+	Status   string   `json:"status" enum:"open,closed"`
+	Tags     []string `json:"tags" maxItems:"2"`
+	Note     *string  `json:"note" maxLength:"5"`
+	Parent   *order   `json:"parent"`
+}
+
+func TestValidateJSON(t *testing.T) {
+	schema, err := For[order]()
+	if err != nil {
+		t.Fatalf("For error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want string // Substring of the error, empty for valid
+	}{
+		{"valid", `{"id":"ord_1","quantity":2,"status":"open","tags":[],"note":null,"parent":null}`, ""},
+		{"nested valid", `{"id":"ord_2","quantity":1,"status":"closed","tags":["a"],"note":"hi","parent":{"id":"ord_1","quantity":1,"status":"open","tags":[],"note":null,"parent":null}}`, ""},
+		{"missing property", `{"id":"ord_1","quantity":2,"status":"open","tags":[],"note":null}`, `$: missing required property "parent"`},
+		{"extra property", `{"id":"ord_1","quantity":2,"status":"open","tags":[],"note":null,"parent":null,"x":1}`, `unexpected property "x"`},
+		{"wrong type", `{"id":"ord_1","quantity":"2","status":"open","tags":[],"note":null,"parent":null}`, "$.quantity: expected integer, got string"},
+		{"not integer", `{"id":"ord_1","quantity":2.5,"status":"open","tags":[],"note":null,"parent":null}`, "$.quantity: expected integer, got number"},
+		{"maximum", `{"id":"ord_1","quantity":11,"status":"open","tags":[],"note":null,"parent":null}`, "greater than maximum 10"},
+		{"enum", `{"id":"ord_1","quantity":2,"status":"pending","tags":[],"note":null,"parent":null}`, `$.status: value "pending" is not one of the allowed values`},
+		{"pattern", `{"id":"x","quantity":2,"status":"open","tags":[],"note":null,"parent":null}`, "does not match pattern"},
+		{"max items", `{"id":"ord_1","quantity":2,"status":"open","tags":["a","b","c"],"note":null,"parent":null}`, "3 items, want at most 2"},
+		{"max length", `{"id":"ord_1","quantity":2,"status":"open","tags":[],"note":"too long","parent":null}`, "$.note: length 8 is greater than 5"},
+		{"recursive", `{"id":"ord_1","quantity":2,"status":"open","tags":[],"note":null,"parent":{"id":"ord_0"}}`, `$.parent: missing required property "quantity"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSON(schema, []byte(tt.data))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("error = %v, want ValidationError", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
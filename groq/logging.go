@@ -1,11 +1,20 @@
 package groq
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 // Logger is the interface for logging
+//
+// Deprecated: use WithLogHandler for structured logging with log/slog
 type Logger interface {
 	Debug(msg string, args ...interface{})
 	Info(msg string, args ...interface{})
@@ -65,3 +74,167 @@ func init() {
 		defaultLogger = &LeveledLogger{Level: LevelWarn}
 	}
 }
+
+// WithLogHandler enables structured logging of every request attempt through
+// h. Each attempt logs a record with method, path, status, attempt, latency
+// and request ID at Info level (Warn for failures); request headers are
+// logged at Debug level with credentials redacted.
+//
+// Without this option, setting GROQ_LOG=info or GROQ_LOG=debug logs to
+// stderr. GROQ_LOG=debug also logs JSON request and response bodies; with
+// this option, use WithLogBodies.
+func WithLogHandler(h slog.Handler) ClientOption {
+	return func(c *ClientConfig) { c.LogHandler = h }
+}
+
+// WithLogBodies adds JSON request and response bodies, truncated and with
+// the API key redacted, to the Debug records of WithLogHandler
+func WithLogBodies() ClientOption {
+	return func(c *ClientConfig) { c.LogBodies = true }
+}
+
+// Redacted replaces credentials in log records
+const Redacted = "[REDACTED]"
+
+// maxLoggedBody caps the size of bodies included in debug records
+const maxLoggedBody = 64 << 10
+
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Api-Key":             true,
+	"X-Api-Key":           true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// envLogHandler returns the stderr handler selected by GROQ_LOG, or nil
+func envLogHandler() slog.Handler {
+	var level slog.Level
+	switch os.Getenv("GROQ_LOG") {
+	case "debug":
+		level = slog.LevelDebug
+	case "info":
+		level = slog.LevelInfo
+	default:
+		return nil
+	}
+	return slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+}
+
+// requestLogger logs request attempts as middleware
+type requestLogger struct {
+	logger *slog.Logger
+	apiKey string
+	bodies bool
+}
+
+func newRequestLogger(h slog.Handler, apiKey string, bodies bool) *requestLogger {
+	return &requestLogger{
+		logger: slog.New(h),
+		apiKey: apiKey,
+		bodies: bodies,
+	}
+}
+
+func (l *requestLogger) middleware(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+	ctx := req.Context()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", RequestAttempt(req)),
+	}
+
+	debug := l.logger.Enabled(ctx, slog.LevelDebug)
+	if debug {
+		debugAttrs := append(attrs, l.headers(req.Header))
+		if l.bodies && isJSON(req.Header) && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				data, _ := io.ReadAll(io.LimitReader(body, maxLoggedBody+1))
+				body.Close()
+				debugAttrs = append(debugAttrs, l.body(data))
+			}
+		}
+		l.logger.LogAttrs(ctx, slog.LevelDebug, "groq: request", debugAttrs...)
+	}
+
+	start := time.Now()
+	resp, err := next(req)
+	attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", l.redact(err.Error())))
+		l.logger.LogAttrs(ctx, slog.LevelWarn, "groq: request failed", attrs...)
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if id := resp.Header.Get(HeaderRequestID); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	if debug && l.bodies && isJSON(resp.Header) {
+		// Buffer the body to log it, then hand it on unchanged
+		data, readErr := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBody+1))
+		resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(data), resp.Body), Closer: resp.Body}
+		if readErr == nil {
+			attrs = append(attrs, l.body(data))
+		}
+	}
+
+	level := slog.LevelInfo
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	l.logger.LogAttrs(ctx, level, "groq: response", attrs...)
+	return resp, nil
+}
+
+func (l *requestLogger) headers(h http.Header) slog.Attr {
+	attrs := make([]any, 0, len(h))
+	for key, values := range h {
+		value := strings.Join(values, ", ")
+		if redactedHeaders[key] {
+			value = Redacted
+		}
+		attrs = append(attrs, slog.String(key, l.redact(value)))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+func (l *requestLogger) body(data []byte) slog.Attr {
+	s := string(data)
+	if len(data) > maxLoggedBody {
+		s = s[:maxLoggedBody] + "...(truncated)"
+	}
+	return slog.String("body", l.redact(s))
+}
+
+// redact removes the API key wherever it appears
+func (l *requestLogger) redact(s string) string {
+	if l.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, l.apiKey, Redacted)
+}
+
+func isJSON(h http.Header) bool {
+	return strings.HasPrefix(h.Get("Content-Type"), "application/json")
+}
+
+// prefixedBody replays bytes already read from a body before the rest of it
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// warn logs through the structured logger if configured, or the legacy Logger
+func (c *Client) warn(ctx context.Context, msg string, err error) {
+	if c.logger != nil {
+		c.logger.logger.LogAttrs(ctx, slog.LevelWarn, "groq: "+msg, slog.String("error", err.Error()))
+		return
+	}
+	if c.config.Logger != nil {
+		c.config.Logger.Warn("%s: %v", msg, err)
+	}
+}
@@ -0,0 +1,222 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// recordBuffer collects JSON log records
+type recordBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *recordBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *recordBuffer) records(t *testing.T) []map[string]interface{} {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestWithLogHandler(t *testing.T) {
+	t.Setenv("GROQ_LOG", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRequestID, "req_abc")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-1"}`)
+	}))
+	defer server.Close()
+
+	var buf recordBuffer
+	c, err := NewClient(
+		WithAPIKey("gsk_secret"),
+		WithBaseURL(server.URL),
+		WithLogHandler(slog.NewJSONHandler(&buf, nil)),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	var result types.ChatCompletion
+	if err := c.Post(context.Background(), "/chat/completions", map[string]string{"model": "m"}, &result); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	if result.ID != "chatcmpl-1" {
+		t.Errorf("ID = %q, response body not passed through", result.ID)
+	}
+
+	records := buf.records(t)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1 at Info level: %v", len(records), records)
+	}
+	rec := records[0]
+	want := map[string]interface{}{
+		"msg":        "groq: response",
+		"level":      "INFO",
+		"method":     "POST",
+		"path":       "/chat/completions",
+		"status":     float64(200),
+		"attempt":    float64(0),
+		"request_id": "req_abc",
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("%s = %v, want %v", k, rec[k], v)
+		}
+	}
+	if _, ok := rec["latency"]; !ok {
+		t.Error("latency missing")
+	}
+}
+
+func TestWithLogHandler_DebugRedaction(t *testing.T) {
+	t.Setenv("GROQ_LOG", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"bad key gsk_secret"}}`)
+	}))
+	defer server.Close()
+
+	var buf recordBuffer
+	c, err := NewClient(
+		WithAPIKey("gsk_secret"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+		WithHeader("X-Api-Key", "other"),
+		WithLogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		WithLogBodies(),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	err = c.Post(context.Background(), "/chat/completions", map[string]string{"prompt": "hello"}, nil)
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if !strings.Contains(apiErr.Message, "gsk_secret") {
+		t.Errorf("error body altered by logging: %q", apiErr.Message)
+	}
+
+	out := func() string {
+		buf.mu.Lock()
+		defer buf.mu.Unlock()
+		return buf.buf.String()
+	}()
+	if strings.Contains(out, "gsk_secret") || strings.Contains(out, `"other"`) {
+		t.Errorf("credentials leaked into logs: %s", out)
+	}
+
+	records := buf.records(t)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want request and response", len(records))
+	}
+
+	req := records[0]
+	headers, _ := req["headers"].(map[string]interface{})
+	if headers["Authorization"] != Redacted {
+		t.Errorf("Authorization = %v, want %s", headers["Authorization"], Redacted)
+	}
+	if body, _ := req["body"].(string); !strings.Contains(body, `"prompt":"hello"`) {
+		t.Errorf("request body = %q", body)
+	}
+
+	resp := records[1]
+	if resp["level"] != "WARN" {
+		t.Errorf("level = %v, want WARN for error status", resp["level"])
+	}
+	if body, _ := resp["body"].(string); !strings.Contains(body, "bad key "+Redacted) {
+		t.Errorf("response body = %q", body)
+	}
+}
+
+func TestWithLogHandler_BodiesOptIn(t *testing.T) {
+	// GROQ_LOG does not change the records of a handler passed in code
+	t.Setenv("GROQ_LOG", "debug")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-1"}`)
+	}))
+	defer server.Close()
+
+	var buf recordBuffer
+	c, err := NewClient(
+		WithAPIKey("gsk_secret"),
+		WithBaseURL(server.URL),
+		WithLogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := c.Post(context.Background(), "/chat/completions", map[string]string{"prompt": "hello"}, nil); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	records := buf.records(t)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want request and response", len(records))
+	}
+	for _, rec := range records {
+		if _, ok := rec["body"]; ok {
+			t.Errorf("body logged without WithLogBodies: %v", rec)
+		}
+	}
+}
+
+func TestEnvLogHandler(t *testing.T) {
+	tests := []struct {
+		env     string
+		enabled bool
+		debug   bool
+	}{
+		{"", false, false},
+		{"warn", false, false},
+		{"info", true, false},
+		{"debug", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("GROQ_LOG", tt.env)
+			h := envLogHandler()
+			if (h != nil) != tt.enabled {
+				t.Fatalf("handler = %v, want enabled %v", h, tt.enabled)
+			}
+			if h != nil && h.Enabled(context.Background(), slog.LevelDebug) != tt.debug {
+				t.Errorf("debug enabled = %v, want %v", !tt.debug, tt.debug)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+)
+
+// MiddlewareNext sends the request to the next middleware in the chain, or
+// over the network for the last one
+type MiddlewareNext func(req *http.Request) (*http.Response, error)
+
+// Middleware intercepts every attempt of every request made by the client,
+// including retries. It may modify the request, short-circuit with its own
+// response or error, or inspect the response returned by next.
+type Middleware func(req *http.Request, next MiddlewareNext) (*http.Response, error)
+
+// WithMiddleware appends middleware to the client's HTTP pipeline. Middleware
+// runs in the order it is added: the first one sees the request first and
+// the response last.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *ClientConfig) { c.Middleware = append(c.Middleware, mw...) }
+}
+
+// chainMiddleware wraps send with mw so that mw[0] is the outermost layer
+func chainMiddleware(send MiddlewareNext, mw []Middleware) MiddlewareNext {
+	next := send
+	for i := len(mw) - 1; i >= 0; i-- {
+		m, n := mw[i], next
+		next = func(req *http.Request) (*http.Response, error) {
+			return m(req, n)
+		}
+	}
+	return next
+}
+
+type attemptKey struct{}
+
+// RequestAttempt returns the zero-based attempt number of a request seen by
+// middleware: 0 for the first attempt, 1 for the first retry, and so on.
+func RequestAttempt(req *http.Request) int {
+	return AttemptFromContext(req.Context())
+}
+
+// AttemptFromContext returns the zero-based attempt number stored in the
+// context of a request attempt, or 0 if there is none
+func AttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
@@ -0,0 +1,124 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Signed"); got != "outer,inner" {
+			t.Errorf("X-Signed = %q, want outer,inner", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	outer := func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+		order = append(order, "outer-before")
+		req.Header.Set("X-Signed", "outer")
+		resp, err := next(req)
+		order = append(order, "outer-after")
+		return resp, err
+	}
+	inner := func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+		order = append(order, "inner-before")
+		req.Header.Set("X-Signed", req.Header.Get("X-Signed")+",inner")
+		resp, err := next(req)
+		order = append(order, "inner-after")
+		return resp, err
+	}
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMiddleware(outer, inner),
+	)
+
+	if err := c.Get(context.Background(), "/test", nil); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	want := []string{"outer-before", "inner-before", "inner-after", "outer-after"}
+	if strings.Join(order, " ") != strings.Join(want, " ") {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestClient_MiddlewareRunsPerAttempt(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var seen []int
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(2),
+		WithMiddleware(func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+			seen = append(seen, RequestAttempt(req))
+			return next(req)
+		}),
+	)
+
+	if err := c.Post(context.Background(), "/test", map[string]string{}, nil); err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != 0 || seen[1] != 1 {
+		t.Errorf("middleware attempts = %v, want [0 1]", seen)
+	}
+}
+
+func TestClient_MiddlewareShortCircuit(t *testing.T) {
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL("http://unreachable.invalid"),
+		WithMiddleware(func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"cached":true}`)),
+				Request:    req,
+			}, nil
+		}),
+	)
+
+	var result map[string]bool
+	if err := c.Get(context.Background(), "/test", &result); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if !result["cached"] {
+		t.Error("expected response from middleware")
+	}
+}
+
+func TestClient_MiddlewareError(t *testing.T) {
+	wantErr := errors.New("signing failed")
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithMaxRetries(0),
+		WithMiddleware(func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+			return nil, wantErr
+		}),
+	)
+
+	err := c.Get(context.Background(), "/test", nil)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("error = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// listCache holds the last model listing fetched by ListCached
+type listCache struct {
+	mu        sync.Mutex
+	list      *types.ModelListResponse
+	fetchedAt time.Time
+	fetch     *listFetch       // Fetch in flight, nil if none
+	now       func() time.Time // Replaced in tests
+}
+
+// listFetch is a List call that concurrent ListCached callers wait on
+type listFetch struct {
+	done chan struct{} // Closed once list or err is set
+	list *types.ModelListResponse
+	err  error
+}
+
+// ListCached returns the model listing, fetching it only if the cached copy
+// is older than ttl. Concurrent callers share a single fetch and stop
+// waiting for it when their ctx is done. Use it on hot paths such as picking
+// a model per request.
+func (m *Models) ListCached(ctx context.Context, ttl time.Duration, opts ...option.RequestOption) (*types.ModelListResponse, error) {
+	c := &m.cache
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+
+	for {
+		c.mu.Lock()
+		if c.list != nil && now().Sub(c.fetchedAt) < ttl {
+			list := copyList(c.list)
+			c.mu.Unlock()
+			return list, nil
+		}
+		f := c.fetch
+		if f == nil {
+			f = &listFetch{done: make(chan struct{})}
+			c.fetch = f
+			c.mu.Unlock()
+			m.fetchList(ctx, f, now, opts)
+			if f.err != nil {
+				return nil, f.err
+			}
+			return copyList(f.list), nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if f.err == nil {
+			return copyList(f.list), nil
+		}
+		// Fetch again with this ctx if the fetching caller gave up
+		if ctx.Err() != nil || !(errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+			return nil, f.err
+		}
+	}
+}
+
+// fetchList runs f without holding the cache lock and caches its result
+func (m *Models) fetchList(ctx context.Context, f *listFetch, now func() time.Time, opts []option.RequestOption) {
+	f.list, f.err = m.List(ctx, opts...)
+
+	c := &m.cache
+	c.mu.Lock()
+	c.fetch = nil
+	if f.err == nil {
+		c.list = f.list
+		c.fetchedAt = now()
+	}
+	c.mu.Unlock()
+	close(f.done)
+}
+
+// InvalidateCache drops the listing cached by ListCached
+func (m *Models) InvalidateCache() {
+	m.cache.mu.Lock()
+	defer m.cache.mu.Unlock()
+	m.cache.list = nil
+}
+
+// copyList keeps callers from modifying the cached listing
+func copyList(list *types.ModelListResponse) *types.ModelListResponse {
+	out := *list
+	out.Data = append([]types.Model(nil), list.Data...)
+	return &out
+}
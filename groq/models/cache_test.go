@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func TestModels_ListCached(t *testing.T) {
+	calls := 0
+	m := New(listRequester(t, &calls))
+	now := time.Unix(1700000000, 0)
+	m.cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	first, err := m.ListCached(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("ListCached error: %v", err)
+	}
+	first.Data[0].ID = "modified"
+
+	now = now.Add(30 * time.Second)
+	second, _ := m.ListCached(ctx, time.Minute)
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 within the TTL", calls)
+	}
+	if second.Data[0].ID != "llama-3.3-70b-versatile" {
+		t.Error("caller modified the cached listing")
+	}
+
+	now = now.Add(time.Minute)
+	m.ListCached(ctx, time.Minute)
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 after the TTL", calls)
+	}
+
+	m.InvalidateCache()
+	m.ListCached(ctx, time.Minute)
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 after InvalidateCache", calls)
+	}
+}
+
+func TestModels_ListCachedError(t *testing.T) {
+	fail := true
+	m := New(&mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			if fail {
+				return errors.New("network error")
+			}
+			return listRequester(t, nil).getFunc(ctx, path, result, opts...)
+		},
+	})
+
+	if _, err := m.ListCached(context.Background(), time.Minute); err == nil {
+		t.Fatal("expected error")
+	}
+	// Errors are not cached
+	fail = false
+	if list, err := m.ListCached(context.Background(), time.Minute); err != nil || len(list.Data) == 0 {
+		t.Errorf("ListCached = %v, %v", list, err)
+	}
+}
+
+func TestModels_ListCachedConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var calls atomic.Int32
+	m := New(&mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			if calls.Add(1) == 1 {
+				close(started)
+			}
+			<-release
+			return listRequester(t, nil).getFunc(ctx, path, result, opts...)
+		},
+	})
+
+	type result struct {
+		list *types.ModelListResponse
+		err  error
+	}
+	first := make(chan result, 1)
+	go func() {
+		list, err := m.ListCached(context.Background(), time.Minute)
+		first <- result{list, err}
+	}()
+	<-started
+
+	// The lock is not held during the fetch, so other callers can give up
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.ListCached(ctx, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting caller error = %v, want deadline exceeded", err)
+	}
+	m.InvalidateCache()
+
+	waiter := make(chan result, 1)
+	go func() {
+		list, err := m.ListCached(context.Background(), time.Minute)
+		waiter <- result{list, err}
+	}()
+	close(release)
+
+	for _, ch := range []chan result{first, waiter} {
+		if res := <-ch; res.err != nil || len(res.list.Data) == 0 {
+			t.Errorf("ListCached = %v, %v", res.list, res.err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1 shared fetch", n)
+	}
+}
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// ModelCapabilities describes what a model supports
+type ModelCapabilities struct {
+	ID                  types.ModelID
+	ContextWindow       int  // In tokens
+	MaxCompletionTokens int  // 0 if unknown
+	Active              bool // False once the model is deactivated
+	Known               bool // Feature flags are documented or registered, not only merged from the API
+
+	Tools      bool // User-defined function tools
+	Vision     bool // Image content parts
+	Reasoning  bool // reasoning_format, reasoning_effort and include_reasoning
+	JSONSchema bool // response_format json_schema
+	Documents  bool // The documents request field
+}
+
+// builtinCapabilities are the documented capabilities of the ModelID
+// constants; RefreshCapabilities updates them from the API
+var builtinCapabilities = []ModelCapabilities{
+	{ID: types.ModelCompoundBeta, ContextWindow: 131072, MaxCompletionTokens: 8192},
+	{ID: types.ModelCompoundBetaMini, ContextWindow: 131072, MaxCompletionTokens: 8192},
+	{ID: types.ModelLlama31_8BInstant, ContextWindow: 131072, MaxCompletionTokens: 131072, Tools: true, Documents: true},
+	{ID: types.ModelLlama33_70BVersatile, ContextWindow: 131072, MaxCompletionTokens: 32768, Tools: true, Documents: true},
+	{ID: types.ModelLlama4Maverick17B128E, ContextWindow: 131072, MaxCompletionTokens: 8192, Tools: true, Vision: true, JSONSchema: true, Documents: true},
+	{ID: types.ModelLlama4Scout17B16E, ContextWindow: 131072, MaxCompletionTokens: 8192, Tools: true, Vision: true, JSONSchema: true, Documents: true},
+	{ID: types.ModelLlamaGuard412B, ContextWindow: 131072, MaxCompletionTokens: 1024, Vision: true},
+	{ID: types.ModelGemma29BIT, ContextWindow: 8192, MaxCompletionTokens: 8192, Tools: true},
+	{ID: types.ModelKimiK2Instruct, ContextWindow: 131072, MaxCompletionTokens: 16384, Tools: true, JSONSchema: true, Documents: true},
+	{ID: types.ModelGPTOSS120B, ContextWindow: 131072, MaxCompletionTokens: 65536, Tools: true, Reasoning: true, JSONSchema: true, Documents: true},
+	{ID: types.ModelGPTOSS20B, ContextWindow: 131072, MaxCompletionTokens: 65536, Tools: true, Reasoning: true, JSONSchema: true, Documents: true},
+	{ID: types.ModelQwen332B, ContextWindow: 131072, MaxCompletionTokens: 40960, Tools: true, Reasoning: true, Documents: true},
+}
+
+var registry = struct {
+	sync.RWMutex
+	models map[types.ModelID]ModelCapabilities
+}{models: make(map[types.ModelID]ModelCapabilities)}
+
+func init() {
+	for _, caps := range builtinCapabilities {
+		caps.Active = true
+		caps.Known = true
+		registry.models[caps.ID] = caps
+	}
+}
+
+// Capabilities returns the capabilities of a model and whether it is known
+func Capabilities(id types.ModelID) (ModelCapabilities, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	caps, ok := registry.models[id]
+	return caps, ok
+}
+
+// RegisterCapabilities adds or replaces the capabilities of a model, e.g.
+// for models released after this version of the SDK. The entry is marked
+// Known so its feature flags are checked.
+func RegisterCapabilities(caps ModelCapabilities) {
+	caps.Known = true
+	registry.Lock()
+	defer registry.Unlock()
+	registry.models[caps.ID] = caps
+}
+
+// MergeModels updates the registry with live model data: the context window,
+// completion limit and active flag of known models, and entries that are not
+// Known for unknown models, whose features are not checked
+func MergeModels(list []types.Model) {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, m := range list {
+		id := types.ModelID(m.ID)
+		caps, ok := registry.models[id]
+		if !ok {
+			caps = ModelCapabilities{ID: id}
+		}
+		caps.Active = m.Active
+		if m.ContextWindow > 0 {
+			caps.ContextWindow = m.ContextWindow
+		}
+		if m.MaxCompletionTokens > 0 {
+			caps.MaxCompletionTokens = m.MaxCompletionTokens
+		}
+		registry.models[id] = caps
+	}
+}
+
+// RefreshCapabilities lists the available models and merges them into the
+// registry with MergeModels
+func (m *Models) RefreshCapabilities(ctx context.Context, opts ...option.RequestOption) error {
+	list, err := m.List(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	MergeModels(list.Data)
+	return nil
+}
+
+// CheckRequest reports the first feature of req that the model does not
+// support as a *types.FieldError. Unregistered models are not checked.
+func CheckRequest(req *types.CreateChatCompletionRequest) error {
+	caps, ok := Capabilities(types.ModelID(req.Model))
+	if !ok {
+		return nil
+	}
+	return caps.Check(req)
+}
+
+// Check reports the first feature of req that the model does not support as
+// a *types.FieldError. Feature flags are only checked if c is Known; the
+// active flag and completion limit always are.
+func (c ModelCapabilities) Check(req *types.CreateChatCompletionRequest) error {
+	if !c.Active {
+		return &types.FieldError{Field: "model", Message: fmt.Sprintf("model %s is not active", c.ID)}
+	}
+	if c.Known {
+		if err := c.checkFeatures(req); err != nil {
+			return err
+		}
+	}
+	if c.MaxCompletionTokens > 0 {
+		limits := []struct {
+			field string
+			value *option.Optional[int]
+		}{{"max_completion_tokens", req.MaxCompletionTokens}, {"max_tokens", req.MaxTokens}}
+		for _, l := range limits {
+			if l.value != nil && l.value.IsSet() && l.value.Value > c.MaxCompletionTokens {
+				return &types.FieldError{Field: l.field, Message: fmt.Sprintf("%d exceeds the %d completion tokens of model %s", l.value.Value, c.MaxCompletionTokens, c.ID)}
+			}
+		}
+	}
+	return nil
+}
+
+// checkFeatures reports the first feature flag of c that req needs
+func (c ModelCapabilities) checkFeatures(req *types.CreateChatCompletionRequest) error {
+	unsupported := func(field, feature string) error {
+		return &types.FieldError{Field: field, Message: fmt.Sprintf("model %s does not support %s", c.ID, feature)}
+	}
+
+	if !c.Reasoning {
+		switch {
+		case req.ReasoningFormat != nil && req.ReasoningFormat.IsSet():
+			return unsupported("reasoning_format", "reasoning")
+		case req.ReasoningEffort != nil && req.ReasoningEffort.IsSet():
+			return unsupported("reasoning_effort", "reasoning")
+		case req.IncludeReasoning != nil && req.IncludeReasoning.IsSet():
+			return unsupported("include_reasoning", "reasoning")
+		}
+	}
+	if !c.Tools {
+		for i, tool := range req.Tools {
+			if tool.Type == "function" {
+				return unsupported("tools["+strconv.Itoa(i)+"]", "function tools")
+			}
+		}
+		if len(req.Functions) > 0 {
+			return unsupported("functions", "function tools")
+		}
+	}
+	if !c.JSONSchema && req.ResponseFormat != nil && req.ResponseFormat.Type == "json_schema" {
+		return unsupported("response_format", "json_schema response formats")
+	}
+	if !c.Documents && len(req.Documents) > 0 {
+		return unsupported("documents", "documents")
+	}
+	if !c.Vision {
+		for i, m := range req.Messages {
+			if hasImage(m.Content) {
+				return unsupported("messages["+strconv.Itoa(i)+"].content", "images")
+			}
+		}
+	}
+	return nil
+}
+
+func hasImage(content interface{}) bool {
+	parts, ok := content.([]types.ContentPart)
+	if !ok {
+		return false
+	}
+	for _, part := range parts {
+		switch part.(type) {
+		case types.ContentPartImage, *types.ContentPartImage:
+			return true
+		}
+	}
+	return false
+}
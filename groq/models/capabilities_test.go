@@ -0,0 +1,152 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func TestCapabilities(t *testing.T) {
+	caps, ok := Capabilities(types.ModelGPTOSS120B)
+	if !ok {
+		t.Fatal("gpt-oss-120b not registered")
+	}
+	if !caps.Reasoning || !caps.Tools || !caps.Active || caps.ContextWindow != 131072 {
+		t.Errorf("capabilities = %+v", caps)
+	}
+
+	if _, ok := Capabilities("no-such-model"); ok {
+		t.Error("unknown model reported as known")
+	}
+}
+
+func TestModelCapabilities_Check(t *testing.T) {
+	imageMessage := types.ChatCompletionMessageParam{
+		Role: types.RoleUser,
+		Content: []types.ContentPart{
+			types.ContentPartImage{Type: "image_url", ImageURL: types.ContentPartImage_ImageURL{URL: "https://example.com/a.png"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		model     types.ModelID
+		req       types.CreateChatCompletionRequest
+		wantField string
+	}{
+		{"reasoning supported", types.ModelQwen332B, types.CreateChatCompletionRequest{ReasoningFormat: option.Ptr(option.Some("parsed"))}, ""},
+		{"reasoning_format", types.ModelLlama33_70BVersatile, types.CreateChatCompletionRequest{ReasoningFormat: option.Ptr(option.Some("parsed"))}, "reasoning_format"},
+		{"reasoning_effort", types.ModelLlama31_8BInstant, types.CreateChatCompletionRequest{ReasoningEffort: option.Ptr(option.Some("high"))}, "reasoning_effort"},
+		{"tools", types.ModelCompoundBeta, types.CreateChatCompletionRequest{Tools: []types.ChatCompletionTool{{Type: "function"}}}, "tools[0]"},
+		{"json_schema", types.ModelLlama33_70BVersatile, types.CreateChatCompletionRequest{ResponseFormat: types.NewJSONSchemaFormat("out", nil, true)}, "response_format"},
+		{"json_object", types.ModelLlama33_70BVersatile, types.CreateChatCompletionRequest{ResponseFormat: types.ResponseFormatJSONObject()}, ""},
+		{"vision", types.ModelLlama33_70BVersatile, types.CreateChatCompletionRequest{Messages: []types.ChatCompletionMessageParam{imageMessage}}, "messages[0].content"},
+		{"vision supported", types.ModelLlama4Scout17B16E, types.CreateChatCompletionRequest{Messages: []types.ChatCompletionMessageParam{imageMessage}}, ""},
+		{"documents", types.ModelGemma29BIT, types.CreateChatCompletionRequest{Documents: []types.Document{{}}}, "documents"},
+		{"max_completion_tokens", types.ModelLlama4Scout17B16E, types.CreateChatCompletionRequest{MaxCompletionTokens: option.Ptr(option.Some(10000))}, "max_completion_tokens"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Model = string(tt.model)
+			err := CheckRequest(&tt.req)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var fieldErr *types.FieldError
+			if !errors.As(err, &fieldErr) || fieldErr.Field != tt.wantField {
+				t.Errorf("error = %v, want field %q", err, tt.wantField)
+			}
+		})
+	}
+
+	// Unknown models are not checked
+	unknown := &types.CreateChatCompletionRequest{Model: "new-model", ReasoningFormat: option.Ptr(option.Some("parsed"))}
+	if err := CheckRequest(unknown); err != nil {
+		t.Errorf("unknown model: %v", err)
+	}
+}
+
+func TestModels_RefreshCapabilities(t *testing.T) {
+	before, _ := Capabilities(types.ModelGemma29BIT)
+	t.Cleanup(func() {
+		RegisterCapabilities(before)
+		registry.Lock()
+		delete(registry.models, "brand-new-model")
+		registry.Unlock()
+	})
+
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			*result.(*types.ModelListResponse) = types.ModelListResponse{Data: []types.Model{
+				{ID: string(types.ModelGemma29BIT), Active: false, ContextWindow: 16384},
+				{ID: "brand-new-model", Active: true, ContextWindow: 262144},
+			}}
+			return nil
+		},
+	}
+	if err := New(mock).RefreshCapabilities(context.Background()); err != nil {
+		t.Fatalf("RefreshCapabilities error: %v", err)
+	}
+
+	gemma, _ := Capabilities(types.ModelGemma29BIT)
+	if gemma.Active || gemma.ContextWindow != 16384 || !gemma.Tools {
+		t.Errorf("merged gemma = %+v", gemma)
+	}
+	req := &types.CreateChatCompletionRequest{Model: string(types.ModelGemma29BIT)}
+	if err := CheckRequest(req); err == nil {
+		t.Error("inactive model accepted")
+	}
+
+	fresh, ok := Capabilities("brand-new-model")
+	if !ok || !fresh.Active || fresh.Known || fresh.ContextWindow != 262144 || fresh.Tools {
+		t.Errorf("new model = %+v, %v", fresh, ok)
+	}
+}
+
+func TestMergeModels_UnknownModel(t *testing.T) {
+	t.Cleanup(func() {
+		registry.Lock()
+		delete(registry.models, "merged-model")
+		delete(registry.models, "retired-model")
+		registry.Unlock()
+	})
+
+	MergeModels([]types.Model{
+		{ID: "merged-model", Active: true, MaxCompletionTokens: 1000},
+		{ID: "retired-model", Active: false},
+	})
+
+	// Feature flags of merged-only entries are unknown, so they are not checked
+	req := &types.CreateChatCompletionRequest{
+		Model:           "merged-model",
+		Tools:           []types.ChatCompletionTool{{Type: "function"}},
+		ReasoningFormat: option.Ptr(option.Some("parsed")),
+	}
+	if err := CheckRequest(req); err != nil {
+		t.Errorf("merged model with tools: %v", err)
+	}
+
+	req.MaxCompletionTokens = option.Ptr(option.Some(2000))
+	var fieldErr *types.FieldError
+	if err := CheckRequest(req); !errors.As(err, &fieldErr) || fieldErr.Field != "max_completion_tokens" {
+		t.Errorf("error = %v, want field max_completion_tokens", err)
+	}
+
+	retired := &types.CreateChatCompletionRequest{Model: "retired-model", Tools: req.Tools}
+	if err := CheckRequest(retired); !errors.As(err, &fieldErr) || fieldErr.Field != "model" {
+		t.Errorf("error = %v, want field model", err)
+	}
+
+	RegisterCapabilities(ModelCapabilities{ID: "merged-model", Active: true})
+	req.MaxCompletionTokens = nil
+	if err := CheckRequest(req); !errors.As(err, &fieldErr) || fieldErr.Field != "reasoning_format" {
+		t.Errorf("registered model: error = %v, want field reasoning_format", err)
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// ModelFilter reports whether a model should be kept
+type ModelFilter func(types.Model) bool
+
+// nonChatModels are ID fragments of speech, transcription and moderation
+// models
+var nonChatModels = []string{"whisper", "tts", "guard"}
+
+// FilterActive keeps active models
+func FilterActive() ModelFilter {
+	return func(m types.Model) bool { return m.Active }
+}
+
+// FilterChat keeps models that serve chat completions, excluding speech,
+// transcription and moderation models
+func FilterChat() ModelFilter {
+	return func(m types.Model) bool {
+		id := strings.ToLower(m.ID)
+		for _, fragment := range nonChatModels {
+			if strings.Contains(id, fragment) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// FilterActiveChat keeps active chat models
+func FilterActiveChat() ModelFilter {
+	return FilterAll(FilterActive(), FilterChat())
+}
+
+// FilterMinContextWindow keeps models with a context window of at least
+// tokens
+func FilterMinContextWindow(tokens int) ModelFilter {
+	return func(m types.Model) bool { return m.ContextWindow >= tokens }
+}
+
+// FilterOwnedBy keeps models of owner, e.g. "Meta" or "OpenAI". The
+// comparison is case-insensitive.
+func FilterOwnedBy(owner string) ModelFilter {
+	return func(m types.Model) bool { return strings.EqualFold(m.OwnedBy, owner) }
+}
+
+// FilterAll keeps models that pass every filter
+func FilterAll(filters ...ModelFilter) ModelFilter {
+	return func(m types.Model) bool {
+		for _, f := range filters {
+			if !f(m) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Filter returns the models that pass filter, in order
+func Filter(list []types.Model, filter ModelFilter) []types.Model {
+	var out []types.Model
+	for _, m := range list {
+		if filter(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// ListFiltered lists the available models that pass filter
+//
+//	chatModels, err := client.Models.ListFiltered(ctx, models.FilterAll(
+//		models.FilterActiveChat(),
+//		models.FilterMinContextWindow(128000),
+//	))
+func (m *Models) ListFiltered(ctx context.Context, filter ModelFilter, opts ...option.RequestOption) ([]types.Model, error) {
+	list, err := m.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return Filter(list.Data, filter), nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+const modelListJSON = `{"object":"list","data":[
+	{"id":"llama-3.3-70b-versatile","object":"model","created":1733447754,"owned_by":"Meta","active":true,"context_window":131072,"public_apps":null,"max_completion_tokens":32768},
+	{"id":"gemma2-9b-it","object":"model","created":1693721698,"owned_by":"Google","active":false,"context_window":8192,"public_apps":null,"max_completion_tokens":8192},
+	{"id":"whisper-large-v3","object":"model","created":1693721698,"owned_by":"OpenAI","active":true,"context_window":448,"public_apps":null,"max_completion_tokens":448},
+	{"id":"openai/gpt-oss-120b","object":"model","created":1754408224,"owned_by":"OpenAI","active":true,"context_window":131072,"public_apps":null,"max_completion_tokens":65536},
+	{"id":"meta-llama/llama-guard-4-12b","object":"model","created":1746743847,"owned_by":"Meta","active":true,"context_window":131072,"public_apps":null,"max_completion_tokens":1024}
+]}`
+
+func listRequester(t *testing.T, calls *int) *mockRequester {
+	return &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			if calls != nil {
+				*calls++
+			}
+			return json.Unmarshal([]byte(modelListJSON), result)
+		},
+	}
+}
+
+func TestModel_Decode(t *testing.T) {
+	var list types.ModelListResponse
+	if err := json.Unmarshal([]byte(modelListJSON), &list); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	m := list.Data[0]
+	if !m.Active || m.ContextWindow != 131072 || m.MaxCompletionTokens != 32768 || m.OwnedBy != "Meta" {
+		t.Errorf("model = %+v", m)
+	}
+}
+
+func TestModels_ListFiltered(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ModelFilter
+		want   []string
+	}{
+		{"active chat", FilterActiveChat(), []string{"llama-3.3-70b-versatile", "openai/gpt-oss-120b"}},
+		{"min context window", FilterMinContextWindow(100000), []string{"llama-3.3-70b-versatile", "openai/gpt-oss-120b", "meta-llama/llama-guard-4-12b"}},
+		{"owned by", FilterOwnedBy("openai"), []string{"whisper-large-v3", "openai/gpt-oss-120b"}},
+		{"combined", FilterAll(FilterActiveChat(), FilterOwnedBy("Meta")), []string{"llama-3.3-70b-versatile"}},
+		{"none", FilterMinContextWindow(1 << 20), nil},
+	}
+
+	m := New(listRequester(t, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.ListFiltered(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("ListFiltered error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d models, want %v", len(got), tt.want)
+			}
+			for i, model := range got {
+				if model.ID != tt.want[i] {
+					t.Errorf("model %d = %s, want %s", i, model.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
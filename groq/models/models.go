@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/pagination"
 	"github.com/ZaguanLabs/groq-go/groq/types"
 )
 
@@ -17,6 +18,7 @@ type Requester interface {
 // Models handles model requests
 type Models struct {
 	requester Requester
+	cache     listCache
 }
 
 // New creates a new Models service
@@ -34,6 +36,19 @@ func (m *Models) List(ctx context.Context, opts ...option.RequestOption) (*types
 	return &result, nil
 }
 
+// ListAutoPaging iterates over the available models. The models endpoint
+// returns every model in a single page; ListAutoPaging gives it the same
+// iterator as the paginated list endpoints.
+func (m *Models) ListAutoPaging(ctx context.Context, opts ...option.RequestOption) *pagination.Iter[types.Model] {
+	return pagination.NewIter(ctx, func(ctx context.Context, cursor string) (*pagination.Page[types.Model], error) {
+		list, err := m.List(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &pagination.Page[types.Model]{Data: list.Data}, nil
+	})
+}
+
 // Retrieve retrieves a model instance, providing basic information about the model such as the owner and permissioning
 func (m *Models) Retrieve(ctx context.Context, modelID string, opts ...option.RequestOption) (*types.Model, error) {
 	var result types.Model
@@ -378,3 +378,29 @@ func TestModels_DeleteWithContext(t *testing.T) {
 		t.Fatal("expected context cancellation error")
 	}
 }
+
+func TestModels_ListAutoPaging(t *testing.T) {
+	calls := 0
+	mock := &mockRequester{
+		getFunc: func(ctx context.Context, path string, result interface{}, opts ...option.RequestOption) error {
+			calls++
+			*result.(*types.ModelListResponse) = types.ModelListResponse{
+				Object: "list",
+				Data:   []types.Model{{ID: "model-1"}, {ID: "model-2"}},
+			}
+			return nil
+		},
+	}
+
+	m := New(mock)
+	var ids []string
+	for model, err := range m.ListAutoPaging(context.Background()).All() {
+		if err != nil {
+			t.Fatalf("ListAutoPaging error: %v", err)
+		}
+		ids = append(ids, model.ID)
+	}
+	if strings.Join(ids, ",") != "model-1,model-2" || calls != 1 {
+		t.Errorf("ids = %v after %d calls", ids, calls)
+	}
+}
@@ -1,6 +1,7 @@
 package option
 
 import (
+	"net/http"
 	"time"
 )
 
@@ -8,21 +9,54 @@ import (
 type RequestOptions struct {
 	Headers     map[string]string
 	QueryParams map[string]string
-	Timeout     *time.Duration
+	Timeout     *time.Duration // Total deadline for the call, including retries
 	MaxRetries  *int
 
+	AttemptTimeout    *time.Duration // Deadline for a single attempt
+	StreamIdleTimeout *time.Duration // Maximum gap between reads of a stream
+
 	// Internal
 	IdempotencyKey string
+
+	// OnResponse hooks are called with the final response of a request,
+	// after retries and before the body is read
+	OnResponse []func(*ResponseInfo)
+
+	// UploadProgress is called as the files of a multipart request are sent
+	UploadProgress func(sent, total int64)
+}
+
+// ResponseInfo describes the final HTTP response of a request
+type ResponseInfo struct {
+	Response  *http.Response
+	RequestID string        // Value of the x-request-id header, useful for support tickets
+	Attempts  int           // Number of attempts made, including retries
+	Elapsed   time.Duration // Time from the first attempt until the final response headers arrived
 }
 
 // RequestOption allows configuring a request
 type RequestOption func(*RequestOptions)
 
-// WithRequestTimeout sets the timeout for a specific request
+// WithRequestTimeout sets the total deadline for a specific request,
+// covering all retry attempts and reading the response, including the whole
+// stream for streaming requests
 func WithRequestTimeout(d time.Duration) RequestOption {
 	return func(o *RequestOptions) { o.Timeout = &d }
 }
 
+// WithRequestAttemptTimeout sets the deadline for each individual attempt of a
+// request, overriding the client timeout. Attempts that time out are retried.
+// For streaming requests it only bounds the wait for response headers.
+func WithRequestAttemptTimeout(d time.Duration) RequestOption {
+	return func(o *RequestOptions) { o.AttemptTimeout = &d }
+}
+
+// WithRequestStreamIdleTimeout aborts a streaming response when no data
+// arrives for longer than d
+func WithRequestStreamIdleTimeout(d time.Duration) RequestOption {
+	return func(o *RequestOptions) { o.StreamIdleTimeout = &d }
+}
+
 // WithRequestMaxRetries sets the max retries for a specific request
 func WithRequestMaxRetries(n int) RequestOption {
 	return func(o *RequestOptions) { o.MaxRetries = &n }
@@ -54,3 +88,35 @@ func WithIdempotencyKey(key string) RequestOption {
 		o.IdempotencyKey = key
 	}
 }
+
+// WithResponseInto stores the final *http.Response of a request in dst, giving
+// access to the status code and headers. For non-streaming calls the body has
+// already been read and closed by the time the call returns.
+func WithResponseInto(dst **http.Response) RequestOption {
+	return func(o *RequestOptions) {
+		o.OnResponse = append(o.OnResponse, func(info *ResponseInfo) {
+			*dst = info.Response
+		})
+	}
+}
+
+// WithResponseInfoInto stores the final response of a request in dst along
+// with its request ID, attempt count and timing
+func WithResponseInfoInto(dst *ResponseInfo) RequestOption {
+	return func(o *RequestOptions) {
+		o.OnResponse = append(o.OnResponse, func(info *ResponseInfo) {
+			*dst = *info
+		})
+	}
+}
+
+// WithUploadProgress calls fn as the files of a multipart request, such as
+// a file upload or transcription, are sent. total is -1 if a file's size is
+// unknown; a retried upload reports from zero again. fn is called from the
+// goroutine writing the request body, not the caller's, so it must be safe
+// for concurrent use.
+func WithUploadProgress(fn func(sent, total int64)) RequestOption {
+	return func(o *RequestOptions) {
+		o.UploadProgress = fn
+	}
+}
@@ -1,6 +1,7 @@
 package option
 
 import (
+	"net/http"
 	"testing"
 	"time"
 )
@@ -130,3 +131,54 @@ func TestRequestOptions_QueryOverwrite(t *testing.T) {
 		t.Errorf("Query param = %s, want value2 (should be overwritten)", opts.QueryParams["param"])
 	}
 }
+
+func TestWithRequestAttemptTimeout(t *testing.T) {
+	opts := &RequestOptions{}
+	timeout := 3 * time.Second
+
+	WithRequestAttemptTimeout(timeout)(opts)
+
+	if opts.AttemptTimeout == nil || *opts.AttemptTimeout != timeout {
+		t.Errorf("AttemptTimeout = %v, want %v", opts.AttemptTimeout, timeout)
+	}
+}
+
+func TestWithRequestStreamIdleTimeout(t *testing.T) {
+	opts := &RequestOptions{}
+	timeout := 15 * time.Second
+
+	WithRequestStreamIdleTimeout(timeout)(opts)
+
+	if opts.StreamIdleTimeout == nil || *opts.StreamIdleTimeout != timeout {
+		t.Errorf("StreamIdleTimeout = %v, want %v", opts.StreamIdleTimeout, timeout)
+	}
+}
+
+func TestWithResponseInto(t *testing.T) {
+	opts := &RequestOptions{}
+	var resp *http.Response
+
+	WithResponseInto(&resp)(opts)
+
+	if len(opts.OnResponse) != 1 {
+		t.Fatalf("len(OnResponse) = %d, want 1", len(opts.OnResponse))
+	}
+
+	want := &http.Response{StatusCode: 200}
+	opts.OnResponse[0](&ResponseInfo{Response: want})
+	if resp != want {
+		t.Error("response not stored")
+	}
+}
+
+func TestWithResponseInfoInto(t *testing.T) {
+	opts := &RequestOptions{}
+	var info ResponseInfo
+
+	WithResponseInfoInto(&info)(opts)
+	opts.OnResponse[0](&ResponseInfo{RequestID: "req_1", Attempts: 2, Elapsed: time.Second})
+
+	if info.RequestID != "req_1" || info.Attempts != 2 || info.Elapsed != time.Second {
+		t.Errorf("info = %+v", info)
+	}
+}
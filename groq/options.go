@@ -1,6 +1,7 @@
 package groq
 
 import (
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -15,10 +16,18 @@ type ClientConfig struct {
 	Headers        map[string]string
 	QueryParams    map[string]string
 
+	// Client-side rate limiting (0 disables)
+	RateLimitRPM int
+	RateLimitTPM int
+
 	// Advanced
-	StrictValidation bool
-	Logger           Logger
+	StrictValidation bool         // Check response Content-Type
+	ValidateRequests bool         // Check request bodies before sending
+	Logger           Logger       // Deprecated: use LogHandler
+	LogHandler       slog.Handler // Structured request logging
+	LogBodies        bool         // Include bodies in Debug log records
 	HTTPClient       *http.Client // Optional custom client
+	Middleware       []Middleware // Applied around every request attempt
 }
 
 // ClientOption allows configuring the Client
@@ -39,7 +48,9 @@ func WithMaxRetries(n int) ClientOption {
 	return func(c *ClientConfig) { c.MaxRetries = n }
 }
 
-// WithTimeout sets the request timeout
+// WithTimeout sets the default timeout for each request attempt. For
+// streaming requests it only bounds the wait for response headers, so long
+// streams are not cut off. Use option.WithRequestTimeout for a total deadline.
 func WithTimeout(d time.Duration) ClientOption {
 	return func(c *ClientConfig) { c.Timeout = d }
 }
@@ -55,6 +66,27 @@ func WithHeader(key, value string) ClientOption {
 }
 
 // WithLogger sets the logger
+//
+// Deprecated: use WithLogHandler
 func WithLogger(l Logger) ClientOption {
 	return func(c *ClientConfig) { c.Logger = l }
 }
+
+// WithRateLimit enables a client-side limiter shared by all requests made
+// through the client. Callers block until rpm requests per minute and tpm
+// estimated tokens per minute allow the request; the budget is adjusted from
+// the rate limit headers of each response. A zero value disables that limit.
+func WithRateLimit(rpm, tpm int) ClientOption {
+	return func(c *ClientConfig) {
+		c.RateLimitRPM = rpm
+		c.RateLimitTPM = tpm
+	}
+}
+
+// WithRequestValidation checks chat completion, speech, transcription and
+// batch requests before they are sent, returning a *ValidationError instead
+// of making a request the API would reject. Without it, chat requests using
+// features the model does not support are only logged as warnings.
+func WithRequestValidation() ClientOption {
+	return func(c *ClientConfig) { c.ValidateRequests = true }
+}
@@ -0,0 +1,35 @@
+package otelgroq
+
+import "go.opentelemetry.io/otel/attribute"
+
+// Attribute keys from the OpenTelemetry GenAI and HTTP semantic conventions,
+// plus Groq-specific timing reported in usage
+const (
+	attrOperationName           = attribute.Key("gen_ai.operation.name")
+	attrProviderName            = attribute.Key("gen_ai.provider.name")
+	attrRequestModel            = attribute.Key("gen_ai.request.model")
+	attrRequestTemperature      = attribute.Key("gen_ai.request.temperature")
+	attrRequestTopP             = attribute.Key("gen_ai.request.top_p")
+	attrRequestMaxTokens        = attribute.Key("gen_ai.request.max_tokens")
+	attrRequestSeed             = attribute.Key("gen_ai.request.seed")
+	attrRequestFrequencyPenalty = attribute.Key("gen_ai.request.frequency_penalty")
+	attrRequestPresencePenalty  = attribute.Key("gen_ai.request.presence_penalty")
+	attrResponseID              = attribute.Key("gen_ai.response.id")
+	attrResponseModel           = attribute.Key("gen_ai.response.model")
+	attrResponseFinishReasons   = attribute.Key("gen_ai.response.finish_reasons")
+	attrUsageInputTokens        = attribute.Key("gen_ai.usage.input_tokens")
+	attrUsageOutputTokens       = attribute.Key("gen_ai.usage.output_tokens")
+	attrTokenType               = attribute.Key("gen_ai.token.type")
+
+	attrServerAddress   = attribute.Key("server.address")
+	attrServerPort      = attribute.Key("server.port")
+	attrHTTPMethod      = attribute.Key("http.request.method")
+	attrHTTPStatusCode  = attribute.Key("http.response.status_code")
+	attrHTTPResendCount = attribute.Key("http.request.resend_count")
+	attrErrorType       = attribute.Key("error.type")
+
+	attrRequestID          = attribute.Key("groq.request.id")
+	attrGroqQueueTime      = attribute.Key("groq.usage.queue_time")
+	attrGroqPromptTime     = attribute.Key("groq.usage.prompt_time")
+	attrGroqCompletionTime = attribute.Key("groq.usage.completion_time")
+)
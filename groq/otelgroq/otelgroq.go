@@ -0,0 +1,442 @@
+// Package otelgroq instruments a groq.Client with OpenTelemetry tracing and
+// metrics following the OpenTelemetry semantic conventions for generative AI.
+//
+// It is a separate module so the core SDK does not depend on OpenTelemetry.
+// Enable it with a client option:
+//
+//	client, err := groq.NewClient(otelgroq.WithTelemetry())
+//
+// Every request attempt produces a client span named "{operation} {model}"
+// carrying the request parameters, response ID and model, finish reasons,
+// token usage, Groq timing (queue, prompt and completion time) and the retry
+// count. Streaming responses keep their span open until the stream has been
+// consumed and record the time to the first chunk.
+package otelgroq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ZaguanLabs/groq-go/groq"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer and meter
+const ScopeName = "github.com/ZaguanLabs/groq-go/groq/otelgroq"
+
+// Operation names
+const (
+	OperationChat       = "chat"
+	OperationEmbeddings = "embeddings"
+)
+
+// ProviderName is the value of gen_ai.provider.name
+const ProviderName = "groq"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. Defaults to the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider sets the meter provider. Defaults to the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+// WithTelemetry returns a client option that instruments every request made
+// by the client
+func WithTelemetry(opts ...Option) groq.ClientOption {
+	return groq.WithMiddleware(Middleware(opts...))
+}
+
+// Middleware returns the instrumentation as a groq.Middleware, for use with
+// groq.WithMiddleware alongside other middleware
+func Middleware(opts ...Option) groq.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newInstrumentation(cfg).middleware
+}
+
+type instrumentation struct {
+	tracer trace.Tracer
+
+	duration   metric.Float64Histogram
+	tokenUsage metric.Int64Histogram
+	firstChunk metric.Float64Histogram
+	queueTime  metric.Float64Histogram
+}
+
+func newInstrumentation(cfg config) *instrumentation {
+	meter := cfg.meterProvider.Meter(ScopeName)
+	inst := &instrumentation{
+		tracer: cfg.tracerProvider.Tracer(ScopeName),
+	}
+
+	var err error
+	inst.duration, err = meter.Float64Histogram("gen_ai.client.operation.duration",
+		metric.WithDescription("GenAI operation duration"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...))
+	handle(err)
+	inst.tokenUsage, err = meter.Int64Histogram("gen_ai.client.token.usage",
+		metric.WithDescription("Measures number of input and output tokens used"),
+		metric.WithUnit("{token}"),
+		metric.WithExplicitBucketBoundaries(tokenBuckets...))
+	handle(err)
+	inst.firstChunk, err = meter.Float64Histogram("groq.client.time_to_first_chunk",
+		metric.WithDescription("Time from sending a streaming request until the first chunk is received"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...))
+	handle(err)
+	inst.queueTime, err = meter.Float64Histogram("groq.client.queue_time",
+		metric.WithDescription("Time the request spent queued on Groq servers, as reported in usage"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...))
+	handle(err)
+
+	return inst
+}
+
+func handle(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+var (
+	durationBuckets = []float64{0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24, 20.48, 40.96, 81.92}
+	tokenBuckets    = []float64{1, 4, 16, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864}
+)
+
+func (inst *instrumentation) middleware(req *http.Request, next groq.MiddlewareNext) (*http.Response, error) {
+	op := operationName(req.URL.Path)
+	params := parseRequest(req)
+
+	attrs := []attribute.KeyValue{
+		attrOperationName.String(op),
+		attrProviderName.String(ProviderName),
+		attrHTTPMethod.String(req.Method),
+	}
+	if params.Model != "" {
+		attrs = append(attrs, attrRequestModel.String(params.Model))
+	}
+	attrs = append(attrs, serverAttributes(req)...)
+
+	spanAttrs := append([]attribute.KeyValue{}, attrs...)
+	spanAttrs = append(spanAttrs, params.attributes()...)
+	if attempt := groq.RequestAttempt(req); attempt > 0 {
+		spanAttrs = append(spanAttrs, attrHTTPResendCount.Int(attempt))
+	}
+
+	ctx, span := inst.tracer.Start(req.Context(), spanName(op, params.Model),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...))
+
+	rec := &record{
+		inst:  inst,
+		span:  span,
+		attrs: attrs,
+		start: time.Now(),
+	}
+
+	resp, err := next(req.WithContext(ctx))
+	if err != nil {
+		rec.fail(fmt.Sprintf("%T", err), err)
+		rec.end()
+		return resp, err
+	}
+
+	span.SetAttributes(attrHTTPStatusCode.Int(resp.StatusCode))
+	if id := resp.Header.Get(groq.HeaderRequestID); id != "" {
+		span.SetAttributes(attrRequestID.String(id))
+	}
+
+	if resp.StatusCode >= 400 {
+		rec.fail(strconv.Itoa(resp.StatusCode), nil)
+		rec.end()
+		return resp, nil
+	}
+
+	if op != OperationChat && op != OperationEmbeddings {
+		rec.end()
+		return resp, nil
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body = newStreamBody(resp.Body, rec)
+		return resp, nil
+	}
+
+	// Buffer the (small) JSON body to read usage, then hand it on untouched
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if readErr != nil {
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{readErr}))
+		rec.fail(fmt.Sprintf("%T", readErr), readErr)
+		rec.end()
+		return resp, nil
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var result responseSummary
+	if json.Unmarshal(body, &result) == nil {
+		rec.observe(&result)
+	}
+	rec.end()
+	return resp, nil
+}
+
+// record accumulates what is known about one request attempt until its
+// span ends
+type record struct {
+	inst  *instrumentation
+	span  trace.Span
+	attrs []attribute.KeyValue // Shared by span and metrics
+	start time.Time
+
+	responseID    string
+	responseModel string
+	finishReasons []string
+	usage         *usage
+	errType       string
+}
+
+func (r *record) observe(s *responseSummary) {
+	if s.ID != "" {
+		r.responseID = s.ID
+	}
+	if s.Model != "" {
+		r.responseModel = s.Model
+	}
+	for _, c := range s.Choices {
+		if c.FinishReason != "" {
+			r.finishReasons = append(r.finishReasons, c.FinishReason)
+		}
+	}
+	if s.Usage != nil {
+		r.usage = s.Usage
+	}
+	if s.XGroq != nil && s.XGroq.Usage != nil {
+		r.usage = s.XGroq.Usage
+	}
+}
+
+func (r *record) ctx() context.Context {
+	return trace.ContextWithSpan(context.Background(), r.span)
+}
+
+func (r *record) fail(errType string, err error) {
+	r.errType = errType
+	if err != nil {
+		r.span.RecordError(err)
+		r.span.SetStatus(codes.Error, err.Error())
+	} else {
+		r.span.SetStatus(codes.Error, "HTTP "+errType)
+	}
+}
+
+func (r *record) end() {
+	attrs := r.attrs
+	if r.responseModel != "" {
+		attrs = append(attrs, attrResponseModel.String(r.responseModel))
+	}
+	if r.errType != "" {
+		attrs = append(attrs, attrErrorType.String(r.errType))
+	}
+
+	spanAttrs := []attribute.KeyValue{}
+	if r.responseModel != "" {
+		spanAttrs = append(spanAttrs, attrResponseModel.String(r.responseModel))
+	}
+	if r.errType != "" {
+		spanAttrs = append(spanAttrs, attrErrorType.String(r.errType))
+	}
+	if r.responseID != "" {
+		spanAttrs = append(spanAttrs, attrResponseID.String(r.responseID))
+	}
+	if len(r.finishReasons) > 0 {
+		spanAttrs = append(spanAttrs, attrResponseFinishReasons.StringSlice(r.finishReasons))
+	}
+
+	ctx := r.ctx()
+	if u := r.usage; u != nil {
+		spanAttrs = append(spanAttrs,
+			attrUsageInputTokens.Int(u.PromptTokens),
+			attrUsageOutputTokens.Int(u.CompletionTokens),
+		)
+		if u.QueueTime > 0 {
+			spanAttrs = append(spanAttrs, attrGroqQueueTime.Float64(u.QueueTime))
+			r.inst.queueTime.Record(ctx, u.QueueTime, metric.WithAttributes(attrs...))
+		}
+		if u.PromptTime > 0 {
+			spanAttrs = append(spanAttrs, attrGroqPromptTime.Float64(u.PromptTime))
+		}
+		if u.CompletionTime > 0 {
+			spanAttrs = append(spanAttrs, attrGroqCompletionTime.Float64(u.CompletionTime))
+		}
+
+		r.inst.tokenUsage.Record(ctx, int64(u.PromptTokens),
+			metric.WithAttributes(append(attrs, attrTokenType.String("input"))...))
+		r.inst.tokenUsage.Record(ctx, int64(u.CompletionTokens),
+			metric.WithAttributes(append(attrs, attrTokenType.String("output"))...))
+	}
+
+	r.inst.duration.Record(ctx, time.Since(r.start).Seconds(), metricAttributes(attrs))
+
+	r.span.SetAttributes(spanAttrs...)
+	r.span.End()
+}
+
+// requestParams holds the request parameters recorded on spans
+type requestParams struct {
+	Model               string   `json:"model"`
+	Temperature         *float64 `json:"temperature"`
+	TopP                *float64 `json:"top_p"`
+	MaxTokens           *int     `json:"max_tokens"`
+	MaxCompletionTokens *int     `json:"max_completion_tokens"`
+	Seed                *int     `json:"seed"`
+	FrequencyPenalty    *float64 `json:"frequency_penalty"`
+	PresencePenalty     *float64 `json:"presence_penalty"`
+}
+
+func (p *requestParams) attributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if p.Temperature != nil {
+		attrs = append(attrs, attrRequestTemperature.Float64(*p.Temperature))
+	}
+	if p.TopP != nil {
+		attrs = append(attrs, attrRequestTopP.Float64(*p.TopP))
+	}
+	if p.MaxCompletionTokens != nil {
+		attrs = append(attrs, attrRequestMaxTokens.Int(*p.MaxCompletionTokens))
+	} else if p.MaxTokens != nil {
+		attrs = append(attrs, attrRequestMaxTokens.Int(*p.MaxTokens))
+	}
+	if p.Seed != nil {
+		attrs = append(attrs, attrRequestSeed.Int(*p.Seed))
+	}
+	if p.FrequencyPenalty != nil {
+		attrs = append(attrs, attrRequestFrequencyPenalty.Float64(*p.FrequencyPenalty))
+	}
+	if p.PresencePenalty != nil {
+		attrs = append(attrs, attrRequestPresencePenalty.Float64(*p.PresencePenalty))
+	}
+	return attrs
+}
+
+// parseRequest reads the parameters of a JSON request body without
+// consuming it
+func parseRequest(req *http.Request) requestParams {
+	var params requestParams
+	if req.GetBody == nil || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return params
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return params
+	}
+	defer body.Close()
+	_ = json.NewDecoder(body).Decode(&params)
+	return params
+}
+
+// responseSummary holds the response fields recorded on spans. It matches
+// chat completions, chat completion chunks and embedding responses.
+type responseSummary struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usage `json:"usage"`
+	XGroq *struct {
+		Usage *usage `json:"usage"`
+	} `json:"x_groq"`
+}
+
+type usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	QueueTime        float64 `json:"queue_time"`
+	PromptTime       float64 `json:"prompt_time"`
+	CompletionTime   float64 `json:"completion_time"`
+}
+
+func operationName(path string) string {
+	switch {
+	case strings.HasSuffix(path, "/chat/completions"):
+		return OperationChat
+	case strings.HasSuffix(path, "/embeddings"):
+		return OperationEmbeddings
+	}
+
+	// Other endpoints are named after their resource, e.g. "audio.speech" or
+	// "files", leaving out IDs to keep the name low-cardinality
+	path = strings.TrimPrefix(path, "/")
+	path = strings.TrimPrefix(path, "openai/")
+	path = strings.TrimPrefix(path, "v1/")
+	parts := strings.Split(path, "/")
+	if parts[0] == "audio" && len(parts) > 1 {
+		return "audio." + parts[1]
+	}
+	return parts[0]
+}
+
+func spanName(op, model string) string {
+	if model == "" {
+		return op
+	}
+	return op + " " + model
+}
+
+func serverAttributes(req *http.Request) []attribute.KeyValue {
+	host, portStr, err := net.SplitHostPort(req.URL.Host)
+	if err != nil {
+		host = req.URL.Host
+		portStr = ""
+		if req.URL.Scheme == "https" {
+			portStr = "443"
+		} else if req.URL.Scheme == "http" {
+			portStr = "80"
+		}
+	}
+	attrs := []attribute.KeyValue{attrServerAddress.String(host)}
+	if port, err := strconv.Atoi(portStr); err == nil {
+		attrs = append(attrs, attrServerPort.Int(port))
+	}
+	return attrs
+}
+
+func metricAttributes(attrs []attribute.KeyValue) metric.MeasurementOption {
+	return metric.WithAttributeSet(attribute.NewSet(attrs...))
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
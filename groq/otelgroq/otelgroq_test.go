@@ -0,0 +1,323 @@
+package otelgroq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ZaguanLabs/groq-go/groq"
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+type telemetry struct {
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*groq.Client, *telemetry) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	tel := &telemetry{
+		spans:  tracetest.NewSpanRecorder(),
+		reader: sdkmetric.NewManualReader(),
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(tel.spans))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(tel.reader))
+
+	client, err := groq.NewClient(
+		groq.WithAPIKey("test-key"),
+		groq.WithBaseURL(server.URL),
+		groq.WithMaxRetries(2),
+		WithTelemetry(WithTracerProvider(tp), WithMeterProvider(mp)),
+	)
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	return client, tel
+}
+
+func (tel *telemetry) metrics(t *testing.T) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := tel.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	out := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			out[m.Name] = m.Data
+		}
+	}
+	return out
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+	return out
+}
+
+func chatRequest() *types.CreateChatCompletionRequest {
+	return &types.CreateChatCompletionRequest{
+		Model:       "llama-3.3-70b-versatile",
+		Messages:    []types.ChatCompletionMessageParam{{Role: types.RoleUser, Content: "Hi"}},
+		Temperature: option.Ptr(option.Some(0.5)),
+		MaxTokens:   option.Ptr(option.Some(64)),
+	}
+}
+
+func TestChatCompletion(t *testing.T) {
+	client, tel := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(groq.HeaderRequestID, "req_123")
+		fmt.Fprint(w, `{"id":"chatcmpl-1","object":"chat.completion","model":"llama-3.3-70b-versatile",
+			"choices":[{"index":0,"message":{"role":"assistant","content":"Hello"},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15,"queue_time":0.01,"prompt_time":0.002,"completion_time":0.02}}`)
+	})
+
+	resp, err := client.Chat.Create(context.Background(), chatRequest())
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if resp.Choices[0].Message.Content != "Hello" {
+		t.Errorf("content = %q, response body was not passed through", resp.Choices[0].Message.Content)
+	}
+
+	spans := tel.spans.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "chat llama-3.3-70b-versatile" {
+		t.Errorf("span name = %q", span.Name())
+	}
+
+	attrs := spanAttrs(span)
+	checks := map[attribute.Key]interface{}{
+		attrOperationName:      "chat",
+		attrProviderName:       "groq",
+		attrRequestModel:       "llama-3.3-70b-versatile",
+		attrRequestTemperature: 0.5,
+		attrRequestMaxTokens:   int64(64),
+		attrResponseID:         "chatcmpl-1",
+		attrResponseModel:      "llama-3.3-70b-versatile",
+		attrUsageInputTokens:   int64(10),
+		attrUsageOutputTokens:  int64(5),
+		attrGroqQueueTime:      0.01,
+		attrHTTPStatusCode:     int64(200),
+		attrRequestID:          "req_123",
+	}
+	for key, want := range checks {
+		got, ok := attrs[key]
+		if !ok {
+			t.Errorf("missing attribute %s", key)
+			continue
+		}
+		if got.AsInterface() != want {
+			t.Errorf("%s = %v, want %v", key, got.AsInterface(), want)
+		}
+	}
+	if got := attrs[attrResponseFinishReasons].AsStringSlice(); len(got) != 1 || got[0] != "stop" {
+		t.Errorf("finish reasons = %v", got)
+	}
+	if _, ok := attrs[attrHTTPResendCount]; ok {
+		t.Error("resend count set on first attempt")
+	}
+
+	metrics := tel.metrics(t)
+	usage, ok := metrics["gen_ai.client.token.usage"].(metricdata.Histogram[int64])
+	if !ok || len(usage.DataPoints) != 2 {
+		t.Fatalf("token usage = %+v, want input and output data points", metrics["gen_ai.client.token.usage"])
+	}
+	for _, dp := range usage.DataPoints {
+		typ, _ := dp.Attributes.Value(attrTokenType)
+		want := map[string]int64{"input": 10, "output": 5}[typ.AsString()]
+		if dp.Sum != want {
+			t.Errorf("%s tokens = %d, want %d", typ.AsString(), dp.Sum, want)
+		}
+	}
+	for _, name := range []string{"gen_ai.client.operation.duration", "groq.client.queue_time"} {
+		if h, ok := metrics[name].(metricdata.Histogram[float64]); !ok || h.DataPoints[0].Count != 1 {
+			t.Errorf("%s not recorded", name)
+		}
+	}
+}
+
+func TestChatCompletion_Stream(t *testing.T) {
+	client, tel := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"chatcmpl-2\",\"model\":\"llama-3.1-8b-instant\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"chatcmpl-2\",\"model\":\"llama-3.1-8b-instant\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"length\"}],")
+		fmt.Fprint(w, "\"x_groq\":{\"id\":\"req_1\",\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":2,\"total_tokens\":9}}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	req := chatRequest()
+	req.Model = "llama-3.1-8b-instant"
+	stream, err := client.Chat.CreateStream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateStream error: %v", err)
+	}
+
+	var content string
+	for {
+		chunk, err := stream.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		if len(chunk.Choices) > 0 {
+			content += chunk.Choices[0].Delta.Content
+		}
+	}
+	stream.Close()
+
+	if content != "Hello" {
+		t.Errorf("content = %q", content)
+	}
+
+	spans := tel.spans.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	attrs := spanAttrs(spans[0])
+	if got := attrs[attrResponseID].AsString(); got != "chatcmpl-2" {
+		t.Errorf("response id = %q", got)
+	}
+	if got := attrs[attrUsageOutputTokens].AsInt64(); got != 2 {
+		t.Errorf("output tokens = %d, want 2", got)
+	}
+	if got := attrs[attrResponseFinishReasons].AsStringSlice(); len(got) != 1 || got[0] != "length" {
+		t.Errorf("finish reasons = %v", got)
+	}
+
+	metrics := tel.metrics(t)
+	if h, ok := metrics["groq.client.time_to_first_chunk"].(metricdata.Histogram[float64]); !ok || h.DataPoints[0].Count != 1 {
+		t.Error("time to first chunk not recorded")
+	}
+}
+
+func TestRetriedAttempts(t *testing.T) {
+	var calls atomic.Int32
+	client, tel := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After-Ms", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"message":"over capacity","type":"internal_server_error"}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-3","choices":[]}`)
+	})
+
+	if _, err := client.Chat.Create(context.Background(), chatRequest()); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	spans := tel.spans.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want one per attempt", len(spans))
+	}
+
+	first := spanAttrs(spans[0])
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("failed attempt status = %v, want Error", spans[0].Status())
+	}
+	if got := first[attrErrorType].AsString(); got != "503" {
+		t.Errorf("error.type = %q, want 503", got)
+	}
+
+	second := spanAttrs(spans[1])
+	if got := second[attrHTTPResendCount].AsInt64(); got != 1 {
+		t.Errorf("resend count = %d, want 1", got)
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Error("successful attempt marked as error")
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	client, err := groq.NewClient(
+		groq.WithAPIKey("test-key"),
+		groq.WithBaseURL("http://127.0.0.1:1"),
+		groq.WithMaxRetries(0),
+		WithTelemetry(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))),
+	)
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+
+	if _, err := client.Chat.Create(context.Background(), chatRequest()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("got %d spans, want 1", len(ended))
+	}
+	if ended[0].Status().Code != codes.Error {
+		t.Error("span not marked as error")
+	}
+	if len(ended[0].Events()) == 0 {
+		t.Error("error not recorded on span")
+	}
+}
+
+func TestNonModelEndpoint(t *testing.T) {
+	client, tel := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"object":"list","data":[]}`)
+	})
+
+	if _, err := client.Models.List(context.Background()); err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+
+	spans := tel.spans.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name() != "models" {
+		t.Errorf("span name = %q, want models", spans[0].Name())
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/openai/v1/chat/completions", OperationChat},
+		{"/openai/v1/embeddings", OperationEmbeddings},
+		{"/openai/v1/audio/transcriptions", "audio.transcriptions"},
+		{"/openai/v1/files/file-123/content", "files"},
+		{"/openai/v1/models", "models"},
+	}
+
+	for _, tt := range tests {
+		if got := operationName(tt.path); got != tt.want {
+			t.Errorf("operationName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
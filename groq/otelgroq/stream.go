@@ -0,0 +1,98 @@
+package otelgroq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// streamBody observes server-sent events as the caller reads them and ends
+// the span once the stream is exhausted or closed
+type streamBody struct {
+	rc  io.ReadCloser
+	rec *record
+
+	// The body is read by the stream decoder while the caller may Close it
+	mu         sync.Mutex
+	buf        []byte
+	firstChunk bool
+	done       bool
+}
+
+func newStreamBody(rc io.ReadCloser, rec *record) *streamBody {
+	return &streamBody{rc: rc, rec: rec}
+}
+
+func (s *streamBody) Read(p []byte) (int, error) {
+	n, err := s.rc.Read(p)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return n, err
+	}
+	if n > 0 {
+		s.scan(p[:n])
+	}
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			s.rec.fail(fmt.Sprintf("%T", err), err)
+		}
+		s.finish()
+	}
+	return n, err
+}
+
+func (s *streamBody) Close() error {
+	s.mu.Lock()
+	s.finish()
+	s.mu.Unlock()
+	return s.rc.Close()
+}
+
+// finish ends the span once; callers hold mu
+func (s *streamBody) finish() {
+	if !s.done {
+		s.done = true
+		s.rec.end()
+	}
+}
+
+// scan parses every complete line received so far
+func (s *streamBody) scan(p []byte) {
+	s.buf = append(s.buf, p...)
+	for {
+		i := bytes.IndexByte(s.buf, '\n')
+		if i < 0 {
+			return
+		}
+		s.line(bytes.TrimRight(s.buf[:i], "\r"))
+		s.buf = s.buf[i+1:]
+	}
+}
+
+func (s *streamBody) line(line []byte) {
+	data, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("[DONE]")) {
+		return
+	}
+
+	if !s.firstChunk {
+		s.firstChunk = true
+		s.rec.inst.firstChunk.Record(s.rec.ctx(), time.Since(s.rec.start).Seconds(),
+			metricAttributes(s.rec.attrs))
+	}
+
+	var chunk responseSummary
+	if json.Unmarshal(data, &chunk) == nil {
+		s.rec.observe(&chunk)
+	}
+}
@@ -0,0 +1,134 @@
+// Package pagination iterates over the pages of cursor-based list endpoints
+package pagination
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// ErrCursorNotAdvancing is returned when a page reports more results but its
+// cursor does not move past the previous page
+var ErrCursorNotAdvancing = errors.New("pagination: next page cursor did not advance")
+
+// Page is one page of a list endpoint
+type Page[T any] struct {
+	Data    []T
+	HasMore bool
+	// NextCursor is passed as the "after" cursor of the next page
+	NextCursor string
+}
+
+// PageFunc fetches the page after cursor, or the first page if cursor is
+// empty
+type PageFunc[T any] func(ctx context.Context, cursor string) (*Page[T], error)
+
+// Iter iterates over the items of a list endpoint, fetching pages as needed.
+// It stops at the first error, including the cancellation of its context.
+//
+//	it := client.Batches.ListAutoPaging(ctx, nil)
+//	for it.Next() {
+//		batch := it.Current()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type Iter[T any] struct {
+	ctx   context.Context
+	fetch PageFunc[T]
+
+	page    []T
+	index   int
+	cursor  string
+	fetched bool // At least one page was fetched
+	done    bool // The last page was fetched
+	current T
+	err     error
+}
+
+// NewIter returns an iterator over the pages fetched by fetch
+func NewIter[T any](ctx context.Context, fetch PageFunc[T]) *Iter[T] {
+	return &Iter[T]{ctx: ctx, fetch: fetch}
+}
+
+// Next advances to the next item, fetching the next page when the current
+// one is exhausted. It returns false at the end or on error.
+func (it *Iter[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.done || !it.nextPage() {
+			return false
+		}
+	}
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+func (it *Iter[T]) nextPage() bool {
+	page, err := it.fetch(it.ctx, it.cursor)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	if !page.HasMore {
+		it.done = true
+	} else if page.NextCursor == "" || (it.fetched && page.NextCursor == it.cursor) {
+		it.err = ErrCursorNotAdvancing
+		return false
+	}
+	it.fetched = true
+	it.cursor = page.NextCursor
+	it.page = page.Data
+	it.index = 0
+	return true
+}
+
+// Current returns the item Next advanced to
+func (it *Iter[T]) Current() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iter[T]) Err() error {
+	return it.err
+}
+
+// All returns the remaining items as an iter.Seq2. An error is yielded once,
+// with the zero value, and ends the sequence.
+//
+//	for batch, err := range client.Batches.ListAutoPaging(ctx, nil).All() {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func (it *Iter[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.current, nil) {
+				return
+			}
+		}
+		if it.err != nil {
+			var zero T
+			yield(zero, it.err)
+		}
+	}
+}
+
+// Collect reads the remaining items into a slice
+func (it *Iter[T]) Collect() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.current)
+	}
+	return items, it.err
+}
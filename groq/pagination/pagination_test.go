@@ -0,0 +1,181 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// pages returns a PageFunc over items split into pages of size n, using the
+// item itself as the cursor
+func pages(items []string, n int, calls *[]string) PageFunc[string] {
+	return func(ctx context.Context, cursor string) (*Page[string], error) {
+		*calls = append(*calls, cursor)
+		start := 0
+		if cursor != "" {
+			start = slices.Index(items, cursor) + 1
+		}
+		end := min(start+n, len(items))
+		page := &Page[string]{Data: items[start:end], HasMore: end < len(items)}
+		if end > start {
+			page.NextCursor = items[end-1]
+		}
+		return page, nil
+	}
+}
+
+func TestIter(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	tests := []struct {
+		name      string
+		pageSize  int
+		wantCalls []string
+	}{
+		{name: "single page", pageSize: 10, wantCalls: []string{""}},
+		{name: "exact pages", pageSize: 5, wantCalls: []string{""}},
+		{name: "multiple pages", pageSize: 2, wantCalls: []string{"", "b", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			it := NewIter(context.Background(), pages(items, tt.pageSize, &calls))
+			var got []string
+			for it.Next() {
+				got = append(got, it.Current())
+			}
+			if it.Err() != nil {
+				t.Fatalf("Err() = %v", it.Err())
+			}
+			if !slices.Equal(got, items) {
+				t.Errorf("items = %v, want %v", got, items)
+			}
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("cursors = %q, want %q", calls, tt.wantCalls)
+			}
+			if it.Next() {
+				t.Error("Next() = true after the end")
+			}
+		})
+	}
+}
+
+func TestIter_Error(t *testing.T) {
+	fetchErr := errors.New("page failed")
+	calls := 0
+	it := NewIter(context.Background(), func(ctx context.Context, cursor string) (*Page[int], error) {
+		calls++
+		if cursor != "" {
+			return nil, fetchErr
+		}
+		return &Page[int]{Data: []int{1, 2}, HasMore: true, NextCursor: "2"}, nil
+	})
+
+	got, err := it.Collect()
+	if !errors.Is(err, fetchErr) {
+		t.Errorf("error = %v, want %v", err, fetchErr)
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("items = %v", got)
+	}
+	if it.Next() || calls != 2 {
+		t.Errorf("iteration continued after error: %d calls", calls)
+	}
+}
+
+func TestIter_EmptyPage(t *testing.T) {
+	it := NewIter(context.Background(), func(ctx context.Context, cursor string) (*Page[int], error) {
+		return &Page[int]{}, nil
+	})
+	if it.Next() || it.Err() != nil {
+		t.Errorf("Next() on empty list, Err() = %v", it.Err())
+	}
+}
+
+func TestIter_CursorNotAdvancing(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty cursor", cursor: ""},
+		{name: "repeated cursor", cursor: "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := NewIter(context.Background(), func(ctx context.Context, cursor string) (*Page[int], error) {
+				return &Page[int]{Data: []int{1}, HasMore: true, NextCursor: tt.cursor}, nil
+			})
+			if _, err := it.Collect(); !errors.Is(err, ErrCursorNotAdvancing) {
+				t.Errorf("error = %v, want ErrCursorNotAdvancing", err)
+			}
+		})
+	}
+}
+
+func TestIter_ContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls []string
+	it := NewIter(ctx, pages([]string{"a", "b", "c"}, 1, &calls))
+	if !it.Next() {
+		t.Fatalf("Next() = false, Err() = %v", it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Error("Next() = true after cancel")
+	}
+	if !errors.Is(it.Err(), context.Canceled) || len(calls) != 1 {
+		t.Errorf("Err() = %v after %d calls", it.Err(), len(calls))
+	}
+}
+
+func TestIter_All(t *testing.T) {
+	var calls []string
+	it := NewIter(context.Background(), pages([]string{"a", "b", "c", "d"}, 2, &calls))
+
+	var got []string
+	for item, err := range it.All() {
+		if err != nil {
+			t.Fatalf("error = %v", err)
+		}
+		got = append(got, item)
+		if item == "a" {
+			break
+		}
+	}
+	if !slices.Equal(got, []string{"a"}) || len(calls) != 1 {
+		t.Errorf("items = %v after %d calls", got, len(calls))
+	}
+
+	// Iteration resumes where the loop stopped
+	for item, err := range it.All() {
+		if err != nil {
+			t.Fatalf("error = %v", err)
+		}
+		got = append(got, item)
+	}
+	if !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("items = %v", got)
+	}
+}
+
+func TestIter_AllError(t *testing.T) {
+	fetchErr := errors.New("page failed")
+	it := NewIter(context.Background(), func(ctx context.Context, cursor string) (*Page[int], error) {
+		return nil, fetchErr
+	})
+
+	var errs []error
+	for item, err := range it.All() {
+		if item != 0 {
+			t.Errorf("item = %d with error", item)
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], fetchErr) {
+		t.Errorf("errors = %v", errs)
+	}
+}
@@ -0,0 +1,166 @@
+package groq
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/chat"
+	"github.com/ZaguanLabs/groq-go/groq/internal/ratelimit"
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// Rate limit response headers
+const (
+	HeaderRateLimitLimitRequests     = "x-ratelimit-limit-requests"
+	HeaderRateLimitLimitTokens       = "x-ratelimit-limit-tokens"
+	HeaderRateLimitRemainingRequests = "x-ratelimit-remaining-requests"
+	HeaderRateLimitRemainingTokens   = "x-ratelimit-remaining-tokens"
+	HeaderRateLimitResetRequests     = "x-ratelimit-reset-requests"
+	HeaderRateLimitResetTokens       = "x-ratelimit-reset-tokens"
+	HeaderRetryAfter                 = "retry-after"
+)
+
+// RateLimitInfo holds the rate limit state reported by the API in response
+// headers. Request limits are per day and token limits are per minute.
+type RateLimitInfo struct {
+	LimitRequests     int           // Maximum requests allowed in the window
+	LimitTokens       int           // Maximum tokens allowed in the window
+	RemainingRequests int           // Requests left in the window
+	RemainingTokens   int           // Tokens left in the window
+	ResetRequests     time.Duration // Time until the request window resets
+	ResetTokens       time.Duration // Time until the token window resets
+	RetryAfter        time.Duration // Only set on 429 responses
+	ObservedAt        time.Time     // When the headers were received
+}
+
+// ResetRequestsAt returns the time at which the request limit resets
+func (r *RateLimitInfo) ResetRequestsAt() time.Time {
+	return r.ObservedAt.Add(r.ResetRequests)
+}
+
+// ResetTokensAt returns the time at which the token limit resets
+func (r *RateLimitInfo) ResetTokensAt() time.Time {
+	return r.ObservedAt.Add(r.ResetTokens)
+}
+
+// ParseRateLimitInfo extracts rate limit information from response headers.
+// It returns nil if none of the rate limit headers are present.
+func ParseRateLimitInfo(h http.Header) *RateLimitInfo {
+	if h == nil {
+		return nil
+	}
+
+	info := &RateLimitInfo{ObservedAt: time.Now()}
+	found := false
+
+	parseInt := func(key string, dst *int) {
+		if v := strings.TrimSpace(h.Get(key)); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				*dst = n
+				found = true
+			}
+		}
+	}
+	parseDuration := func(key string, dst *time.Duration) {
+		if v := strings.TrimSpace(h.Get(key)); v != "" {
+			if d, ok := parseResetDuration(v); ok {
+				*dst = d
+				found = true
+			}
+		}
+	}
+
+	parseInt(HeaderRateLimitLimitRequests, &info.LimitRequests)
+	parseInt(HeaderRateLimitLimitTokens, &info.LimitTokens)
+	parseInt(HeaderRateLimitRemainingRequests, &info.RemainingRequests)
+	parseInt(HeaderRateLimitRemainingTokens, &info.RemainingTokens)
+	parseDuration(HeaderRateLimitResetRequests, &info.ResetRequests)
+	parseDuration(HeaderRateLimitResetTokens, &info.ResetTokens)
+	parseDuration(HeaderRetryAfter, &info.RetryAfter)
+
+	if !found {
+		return nil
+	}
+	return info
+}
+
+// parseResetDuration parses reset values such as "2m59.56s" or "7.66s", and
+// bare numbers of seconds as used by retry-after.
+func parseResetDuration(v string) (time.Duration, bool) {
+	if d, err := time.ParseDuration(v); err == nil {
+		return d, true
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// WithRateLimitInto stores the rate limit information of the final response
+// in dst. dst is left untouched if the response has no rate limit headers.
+func WithRateLimitInto(dst *RateLimitInfo) option.RequestOption {
+	return func(o *option.RequestOptions) {
+		o.OnResponse = append(o.OnResponse, func(resp *option.ResponseInfo) {
+			if info := ParseRateLimitInfo(resp.Response.Header); info != nil {
+				*dst = *info
+			}
+		})
+	}
+}
+
+// limiterState converts the rate limit headers of resp into the state the
+// client-side limiter adapts to. Missing headers are reported as absent
+// rather than zero.
+func limiterState(resp *http.Response) ratelimit.State {
+	var state ratelimit.State
+	info := ParseRateLimitInfo(resp.Header)
+	if info == nil {
+		return state
+	}
+
+	if resp.Header.Get(HeaderRateLimitRemainingRequests) != "" {
+		state.HasRequests = true
+		state.RemainingRequests = info.RemainingRequests
+		state.ResetRequests = info.ResetRequests
+	}
+	if resp.Header.Get(HeaderRateLimitRemainingTokens) != "" {
+		state.HasTokens = true
+		state.RemainingTokens = info.RemainingTokens
+		state.ResetTokens = info.ResetTokens
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		state.RetryAfter = info.RetryAfter
+	}
+	return state
+}
+
+// estimateTokens estimates the token cost of a request body for the
+// client-side limiter. Bodies without a known token cost count as zero.
+func estimateTokens(body interface{}) int {
+	switch b := body.(type) {
+	case *types.CreateChatCompletionRequest:
+		return chat.EstimateTokens(b)
+	case *types.CreateEmbeddingRequest:
+		return estimateEmbeddingTokens(b)
+	default:
+		return 0
+	}
+}
+
+func estimateEmbeddingTokens(req *types.CreateEmbeddingRequest) int {
+	switch input := req.Input.(type) {
+	case string:
+		return chat.EstimateTextTokens(input)
+	case []string:
+		total := 0
+		for _, s := range input {
+			total += chat.EstimateTextTokens(s)
+		}
+		return total
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,195 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func TestParseRateLimitInfo(t *testing.T) {
+	h := make(http.Header)
+	h.Set("x-ratelimit-limit-requests", "14400")
+	h.Set("x-ratelimit-limit-tokens", "18000")
+	h.Set("x-ratelimit-remaining-requests", "14370")
+	h.Set("x-ratelimit-remaining-tokens", "17997")
+	h.Set("x-ratelimit-reset-requests", "2m59.56s")
+	h.Set("x-ratelimit-reset-tokens", "7.66s")
+	h.Set("retry-after", "2")
+
+	info := ParseRateLimitInfo(h)
+	if info == nil {
+		t.Fatal("ParseRateLimitInfo returned nil")
+	}
+
+	if info.LimitRequests != 14400 {
+		t.Errorf("LimitRequests = %d, want 14400", info.LimitRequests)
+	}
+	if info.LimitTokens != 18000 {
+		t.Errorf("LimitTokens = %d, want 18000", info.LimitTokens)
+	}
+	if info.RemainingRequests != 14370 {
+		t.Errorf("RemainingRequests = %d, want 14370", info.RemainingRequests)
+	}
+	if info.RemainingTokens != 17997 {
+		t.Errorf("RemainingTokens = %d, want 17997", info.RemainingTokens)
+	}
+	if want := 2*time.Minute + 59560*time.Millisecond; info.ResetRequests != want {
+		t.Errorf("ResetRequests = %v, want %v", info.ResetRequests, want)
+	}
+	if want := 7660 * time.Millisecond; info.ResetTokens != want {
+		t.Errorf("ResetTokens = %v, want %v", info.ResetTokens, want)
+	}
+	if info.RetryAfter != 2*time.Second {
+		t.Errorf("RetryAfter = %v, want 2s", info.RetryAfter)
+	}
+	if got := info.ResetTokensAt().Sub(info.ObservedAt); got != info.ResetTokens {
+		t.Errorf("ResetTokensAt offset = %v, want %v", got, info.ResetTokens)
+	}
+}
+
+func TestParseRateLimitInfo_NoHeaders(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Content-Type", "application/json")
+
+	if info := ParseRateLimitInfo(h); info != nil {
+		t.Errorf("ParseRateLimitInfo() = %+v, want nil", info)
+	}
+	if info := ParseRateLimitInfo(nil); info != nil {
+		t.Errorf("ParseRateLimitInfo(nil) = %+v, want nil", info)
+	}
+}
+
+func TestClient_WithRateLimitInto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ratelimit-remaining-requests", "99")
+		w.Header().Set("x-ratelimit-remaining-tokens", "5000")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var info RateLimitInfo
+	if err := c.Get(context.Background(), "/test", nil, WithRateLimitInto(&info)); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+
+	if info.RemainingRequests != 99 {
+		t.Errorf("RemainingRequests = %d, want 99", info.RemainingRequests)
+	}
+	if info.RemainingTokens != 5000 {
+		t.Errorf("RemainingTokens = %d, want 5000", info.RemainingTokens)
+	}
+}
+
+func TestClient_RateLimitErrorInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-remaining-tokens", "0")
+		w.Header().Set("x-ratelimit-reset-tokens", "1.5s")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"tokens","code":"rate_limit_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+	)
+
+	err := c.Post(context.Background(), "/test", nil, nil)
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected *RateLimitError, got %T", err)
+	}
+	if rateLimitErr.RateLimit == nil {
+		t.Fatal("RateLimit not set")
+	}
+	if rateLimitErr.RateLimit.ResetTokens != 1500*time.Millisecond {
+		t.Errorf("ResetTokens = %v, want 1.5s", rateLimitErr.RateLimit.ResetTokens)
+	}
+}
+
+func TestClient_WithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRateLimit(1, 0),
+	)
+	if c.limiter == nil {
+		t.Fatal("limiter not configured")
+	}
+
+	if err := c.Get(context.Background(), "/test", nil); err != nil {
+		t.Fatalf("first Get error: %v", err)
+	}
+
+	// The single request per minute is spent, so the next call must block
+	err := c.Get(context.Background(), "/test", nil, option.WithRequestTimeout(50*time.Millisecond))
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError while rate limited, got %T: %v", err, err)
+	}
+}
+
+func TestClient_RateLimitAdaptsToHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ratelimit-remaining-tokens", "0")
+		w.Header().Set("x-ratelimit-reset-tokens", "1m")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRateLimit(0, 100000),
+	)
+
+	if err := c.Get(context.Background(), "/test", nil); err != nil {
+		t.Fatalf("first Get error: %v", err)
+	}
+
+	// The server reported an exhausted token budget
+	err := c.Get(context.Background(), "/test", nil, option.WithRequestTimeout(50*time.Millisecond))
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *TimeoutError while paused, got %T: %v", err, err)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	chatReq := &types.CreateChatCompletionRequest{
+		Messages: []types.ChatCompletionMessageParam{{Role: types.RoleUser, Content: "hello world"}},
+	}
+	if got := estimateTokens(chatReq); got == 0 {
+		t.Error("expected non-zero estimate for chat request")
+	}
+
+	embReq := &types.CreateEmbeddingRequest{Input: []string{"abcd", "abcdefgh"}}
+	if got := estimateTokens(embReq); got != 3 {
+		t.Errorf("embedding estimate = %d, want 3", got)
+	}
+
+	if got := estimateTokens(nil); got != 0 {
+		t.Errorf("nil body estimate = %d, want 0", got)
+	}
+}
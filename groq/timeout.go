@@ -0,0 +1,179 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+)
+
+// Timeout semantics:
+//   - option.WithRequestTimeout sets a total deadline for the whole call,
+//     covering every attempt, backoff between attempts and reading the
+//     response body (including the full stream for streaming calls).
+//   - option.WithRequestAttemptTimeout (default ClientConfig.Timeout) bounds a
+//     single attempt. Expired attempts are retried. For non-streaming calls
+//     it also covers reading the body; for streaming calls it only covers
+//     the wait for response headers, so long streams are not truncated.
+//   - option.WithRequestStreamIdleTimeout bounds the gap between reads of a
+//     streaming response body.
+
+// attemptTimeoutError is returned when a single attempt exceeds its timeout.
+// It implements net.Error so the retry layer treats it as a retryable timeout.
+type attemptTimeoutError struct{ timeout time.Duration }
+
+func (e *attemptTimeoutError) Error() string {
+	return fmt.Sprintf("attempt timed out after %v", e.timeout)
+}
+func (e *attemptTimeoutError) Timeout() bool   { return true }
+func (e *attemptTimeoutError) Temporary() bool { return true }
+
+// idleTimeoutError is returned when a stream produces no data within the
+// idle timeout.
+type idleTimeoutError struct{ timeout time.Duration }
+
+func (e *idleTimeoutError) Error() string {
+	return fmt.Sprintf("stream idle for longer than %v", e.timeout)
+}
+func (e *idleTimeoutError) Timeout() bool   { return true }
+func (e *idleTimeoutError) Temporary() bool { return false }
+
+// withTimeout derives the context for a whole call, applying the total
+// deadline from option.WithRequestTimeout if set.
+func withTimeout(ctx context.Context, opts *option.RequestOptions) (context.Context, context.CancelFunc) {
+	if opts.Timeout != nil && *opts.Timeout > 0 {
+		return context.WithTimeout(ctx, *opts.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (c *Client) attemptTimeout(opts *option.RequestOptions) time.Duration {
+	if opts.AttemptTimeout != nil {
+		return *opts.AttemptTimeout
+	}
+	return c.config.Timeout
+}
+
+// doAttempt sends a single attempt of req under its own cancellable context.
+// The returned response body releases that context when closed.
+func (c *Client) doAttempt(req *http.Request, opts *option.RequestOptions, stream bool, attempt int) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(context.WithValue(req.Context(), attemptKey{}, attempt))
+
+	body := &timeoutBody{req: req, cancel: cancel}
+	if d := c.attemptTimeout(opts); d > 0 {
+		body.attemptTimeout = d
+		body.attemptTimer = time.AfterFunc(d, func() {
+			body.attemptFired.Store(true)
+			cancel()
+		})
+	}
+
+	resp, err := c.send(req.WithContext(ctx))
+	if err == nil && resp == nil {
+		err = errors.New("groq: middleware returned a nil response without an error")
+	}
+	if err != nil {
+		body.stop()
+		cancel()
+		if body.attemptFired.Load() {
+			return nil, &attemptTimeoutError{timeout: body.attemptTimeout}
+		}
+		return nil, err
+	}
+
+	if stream {
+		// Headers have arrived; the rest of the stream is bounded only by
+		// the total deadline and the idle timeout.
+		if body.attemptTimer != nil && !body.attemptTimer.Stop() {
+			resp.Body.Close()
+			cancel()
+			return nil, &attemptTimeoutError{timeout: body.attemptTimeout}
+		}
+		body.attemptTimer = nil
+		if opts.StreamIdleTimeout != nil && *opts.StreamIdleTimeout > 0 {
+			body.idleTimeout = *opts.StreamIdleTimeout
+			body.idleTimer = time.AfterFunc(body.idleTimeout, func() {
+				body.idleFired.Store(true)
+				cancel()
+			})
+		}
+	}
+
+	body.ReadCloser = resp.Body
+	resp.Body = body
+	return resp, nil
+}
+
+// timeoutBody wraps a response body, enforcing the attempt and idle timeouts
+// and translating their expiry into TimeoutError.
+type timeoutBody struct {
+	io.ReadCloser
+	req    *http.Request
+	cancel context.CancelFunc
+
+	attemptTimeout time.Duration
+	attemptTimer   *time.Timer
+	attemptFired   atomic.Bool
+
+	idleTimeout time.Duration
+	idleTimer   *time.Timer
+	idleFired   atomic.Bool
+
+	closeOnce sync.Once
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.idleTimer != nil && n > 0 {
+		b.idleTimer.Reset(b.idleTimeout)
+	}
+	if err != nil && err != io.EOF {
+		switch {
+		case b.idleFired.Load():
+			err = newConnectionError(b.req, &idleTimeoutError{timeout: b.idleTimeout})
+		case b.attemptFired.Load():
+			err = newConnectionError(b.req, &attemptTimeoutError{timeout: b.attemptTimeout})
+		case b.req.Context().Err() == context.DeadlineExceeded:
+			err = newConnectionError(b.req, b.req.Context().Err())
+		}
+	}
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		b.stop()
+		err = b.ReadCloser.Close()
+		b.cancel()
+	})
+	return err
+}
+
+func (b *timeoutBody) stop() {
+	if b.attemptTimer != nil {
+		b.attemptTimer.Stop()
+	}
+	if b.idleTimer != nil {
+		b.idleTimer.Stop()
+	}
+}
+
+// cancelOnClose releases the call context once the caller closes the body of
+// a streaming response.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -10,7 +10,7 @@ type Batch struct {
 	Errors           *BatchErrors        `json:"errors,omitempty"`
 	InputFileID      string              `json:"input_file_id"`
 	CompletionWindow string              `json:"completion_window"`
-	Status           string              `json:"status"` // validating, failed, in_progress, finalizing, completed, expired, cancelling, cancelled
+	Status           BatchStatus         `json:"status"`
 	OutputFileID     string              `json:"output_file_id,omitempty"`
 	ErrorFileID      string              `json:"error_file_id,omitempty"`
 	CreatedAt        int64               `json:"created_at"`
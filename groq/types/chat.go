@@ -77,6 +77,7 @@ type ChatCompletionMessage struct {
 
 // ToolCall represents a tool call
 type ToolCall struct {
+	Index    *int         `json:"index,omitempty"` // Position of the call; set in streaming deltas only
 	ID       string       `json:"id"`
 	Type     string       `json:"type"`
 	Function FunctionCall `json:"function"`
@@ -185,19 +186,19 @@ type CreateChatCompletionRequest struct {
 	PresencePenalty       *option.Optional[float64]    `json:"presence_penalty,omitempty"`
 	ResponseFormat        *ResponseFormat              `json:"response_format,omitempty"`
 	Seed                  *option.Optional[int]        `json:"seed,omitempty"`
-	Stop                  interface{}                  `json:"stop,omitempty"` // string or []string
+	Stop                  *Stop                        `json:"stop,omitempty"` // StopSequence or StopSequences
 	Stream                *option.Optional[bool]       `json:"stream,omitempty"`
 	Temperature           *option.Optional[float64]    `json:"temperature,omitempty"`
 	TopP                  *option.Optional[float64]    `json:"top_p,omitempty"`
 	Tools                 []ChatCompletionTool         `json:"tools,omitempty"`
-	ToolChoice            interface{}                  `json:"tool_choice,omitempty"` // string or ToolChoice object
+	ToolChoice            *ToolChoice                  `json:"tool_choice,omitempty"` // ToolChoiceAuto, ToolChoiceRequired, ToolChoiceNone or ToolChoiceFunction
 	User                  string                       `json:"user,omitempty"`
 	ParallelToolCalls     *option.Optional[bool]       `json:"parallel_tool_calls,omitempty"`
 	DisableToolValidation bool                         `json:"disable_tool_validation,omitempty"`
 
 	// Deprecated: use Tools instead
 	Functions    []FunctionDefinition `json:"functions,omitempty"`
-	FunctionCall interface{}          `json:"function_call,omitempty"` // "none", "auto", "required", or {"name": "func_name"}
+	FunctionCall *FunctionCallChoice  `json:"function_call,omitempty"` // FunctionCallNone, FunctionCallAuto or FunctionCallName
 
 	// Compound AI
 	CompoundCustom *CompoundCustom `json:"compound_custom,omitempty"`
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // ContentPart represents a part of message content (text, image, or document)
 type ContentPart interface {
 	contentPart()
@@ -15,7 +20,7 @@ func (ContentPartText) contentPart() {}
 
 // ContentPartImage represents an image content part
 type ContentPartImage struct {
-	Type     string           `json:"type"` // "image_url"
+	Type     string                    `json:"type"` // "image_url"
 	ImageURL ContentPartImage_ImageURL `json:"image_url"`
 }
 
@@ -37,6 +42,62 @@ func (ContentPartDocument) contentPart() {}
 
 // ContentPartDocument_Document represents the document details
 type ContentPartDocument_Document struct {
-	Data map[string]interface{} `json:"data"` // The JSON document data
+	Data map[string]interface{} `json:"data"`         // The JSON document data
 	ID   *string                `json:"id,omitempty"` // Optional unique identifier for the document
 }
+
+// MarshalJSON sets Type to "text" if it is empty
+func (p ContentPartText) MarshalJSON() ([]byte, error) {
+	if p.Type == "" {
+		p.Type = "text"
+	}
+	type alias ContentPartText
+	return json.Marshal(alias(p))
+}
+
+// MarshalJSON sets Type to "image_url" if it is empty
+func (p ContentPartImage) MarshalJSON() ([]byte, error) {
+	if p.Type == "" {
+		p.Type = "image_url"
+	}
+	type alias ContentPartImage
+	return json.Marshal(alias(p))
+}
+
+// MarshalJSON sets Type to "document" if it is empty
+func (p ContentPartDocument) MarshalJSON() ([]byte, error) {
+	if p.Type == "" {
+		p.Type = "document"
+	}
+	type alias ContentPartDocument
+	return json.Marshal(alias(p))
+}
+
+// UnmarshalContentPart decodes a content part into ContentPartText,
+// ContentPartImage or ContentPartDocument based on its type
+func UnmarshalContentPart(data []byte) (ContentPart, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	switch head.Type {
+	case "text":
+		var p ContentPartText
+		err := json.Unmarshal(data, &p)
+		return p, err
+	case "image_url":
+		var p ContentPartImage
+		err := json.Unmarshal(data, &p)
+		return p, err
+	case "document":
+		var p ContentPartDocument
+		err := json.Unmarshal(data, &p)
+		return p, err
+	case "":
+		return nil, fmt.Errorf("content part has no type")
+	}
+	return nil, fmt.Errorf("unknown content part type %q", head.Type)
+}
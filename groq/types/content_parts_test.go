@@ -177,13 +177,19 @@ func TestMultimodalMessage_JSON(t *testing.T) {
 		t.Errorf("role mismatch: got %s, want %s", decoded.Role, msg.Role)
 	}
 
-	// Content should be preserved as slice
-	content, ok := decoded.Content.([]interface{})
+	// Content should be decoded into typed parts
+	content, ok := decoded.Content.([]ContentPart)
 	if !ok {
-		t.Fatalf("content is not a slice")
+		t.Fatalf("content is %T, want []ContentPart", decoded.Content)
 	}
 
 	if len(content) != 2 {
-		t.Errorf("content length: got %d, want 2", len(content))
+		t.Fatalf("content length: got %d, want 2", len(content))
+	}
+	if text, ok := content[0].(ContentPartText); !ok || text.Text != "Analyze this data:" {
+		t.Errorf("part 0 = %#v, want ContentPartText", content[0])
+	}
+	if doc, ok := content[1].(ContentPartDocument); !ok || doc.Document.ID == nil || *doc.Document.ID != id {
+		t.Errorf("part 1 = %#v, want ContentPartDocument", content[1])
 	}
 }
@@ -22,6 +22,29 @@ const (
 	FinishReasonFunctionCall  FinishReason = "function_call" // Deprecated but still used?
 )
 
+// BatchStatus represents the lifecycle state of a batch
+type BatchStatus string
+
+const (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusExpired    BatchStatus = "expired"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// IsTerminal reports whether the batch will not change status anymore
+func (s BatchStatus) IsTerminal() bool {
+	switch s {
+	case BatchStatusFailed, BatchStatusCompleted, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
 // ModelID represents available Groq model identifiers
 type ModelID string
 
@@ -1,19 +1,43 @@
 package types
 
+import (
+	"io"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+)
+
+// FilePurpose is the intended use of a file
+type FilePurpose string
+
+const (
+	FilePurposeBatch       FilePurpose = "batch"        // Batch input files
+	FilePurposeBatchOutput FilePurpose = "batch_output" // Batch output and error files
+)
+
 // FileObject represents a file
 type FileObject struct {
-	ID        string `json:"id"`
-	Bytes     int64  `json:"bytes"`
-	CreatedAt int64  `json:"created_at"`
-	Filename  string `json:"filename"`
-	Object    string `json:"object"`
-	Purpose   string `json:"purpose"`
+	ID        string      `json:"id"`
+	Bytes     int64       `json:"bytes"`
+	CreatedAt int64       `json:"created_at"`
+	Filename  string      `json:"filename"`
+	Object    string      `json:"object"`
+	Purpose   FilePurpose `json:"purpose"`
 }
 
 // FileListResponse represents a list of files
 type FileListResponse struct {
-	Object string       `json:"object"`
-	Data   []FileObject `json:"data"`
+	Object  string       `json:"object"`
+	Data    []FileObject `json:"data"`
+	FirstID string       `json:"first_id,omitempty"`
+	LastID  string       `json:"last_id,omitempty"`
+	HasMore bool         `json:"has_more"`
+}
+
+// ListFilesRequest represents parameters to list files
+type ListFilesRequest struct {
+	After   *option.Optional[string]      `json:"after,omitempty"`
+	Limit   *option.Optional[int]         `json:"limit,omitempty"`
+	Purpose *option.Optional[FilePurpose] `json:"purpose,omitempty"` // Only list files with this purpose
 }
 
 // FileDeleted represents a deleted file response
@@ -25,6 +49,8 @@ type FileDeleted struct {
 
 // CreateFileRequest represents parameters to upload a file
 type CreateFileRequest struct {
-	File    interface{} `json:"file"` // io.Reader or file path
-	Purpose string      `json:"purpose"`
+	// File is streamed while it is uploaded. Uploads are retried only if it
+	// is an io.Seeker, such as an *os.File, whose name is sent as the filename.
+	File    io.Reader   `json:"file"`
+	Purpose FilePurpose `json:"purpose"`
 }
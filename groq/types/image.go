@@ -0,0 +1,273 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Groq vision limits
+const (
+	MaxImageURLSize    = 20 << 20   // Bytes of an image fetched from a URL
+	MaxImageBase64Size = 4 << 20    // Bytes of a base64-encoded image in the request
+	MaxImagePixels     = 33_177_600 // Resolution (width × height)
+)
+
+// maxDecodePixels caps the resolution decoded for downscaling, since a small
+// file can claim dimensions that would need gigabytes of memory
+const maxDecodePixels = 4 * MaxImagePixels
+
+// Image detail levels
+const (
+	ImageDetailAuto = "auto"
+	ImageDetailLow  = "low"
+	ImageDetailHigh = "high"
+)
+
+var (
+	// ErrUnsupportedImageType is returned for images that are not JPEG, PNG,
+	// GIF or WebP
+	ErrUnsupportedImageType = errors.New("unsupported image type")
+	// ErrImageTooLarge is returned for images over Groq's size or resolution
+	// limits that were not downscaled
+	ErrImageTooLarge = errors.New("image too large")
+)
+
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type imageOptions struct {
+	detail    string
+	maxSide   int
+	downscale bool
+}
+
+// ImageOption configures how an image content part is built
+type ImageOption func(*imageOptions)
+
+// WithImageDetail sets the detail level ("auto", "low" or "high")
+func WithImageDetail(detail string) ImageOption {
+	return func(o *imageOptions) { o.detail = detail }
+}
+
+// WithImageMaxSide downscales the image so that neither side exceeds px
+func WithImageMaxSide(px int) ImageOption {
+	return func(o *imageOptions) { o.maxSide = px }
+}
+
+// WithImageDownscale downscales images over Groq's resolution or encoded
+// size limits instead of returning ErrImageTooLarge. Images over four times
+// the resolution limit are still rejected without being decoded.
+func WithImageDownscale() ImageOption {
+	return func(o *imageOptions) { o.downscale = true }
+}
+
+// ImageFromFile reads a local image into a content part with a base64 data
+// URL. The MIME type is detected from the file contents.
+func ImageFromFile(path, detail string, opts ...ImageOption) (ContentPartImage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentPartImage{}, err
+	}
+	defer f.Close()
+	return ImageFromReader(f, "", append([]ImageOption{WithImageDetail(detail)}, opts...)...)
+}
+
+// ImageFromReader reads an image into a content part with a base64 data
+// URL. If mime is empty it is detected from the data.
+func ImageFromReader(r io.Reader, mime string, opts ...ImageOption) (ContentPartImage, error) {
+	o := imageOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Read one byte past the largest image that could be downscaled to fit
+	data, err := io.ReadAll(io.LimitReader(r, MaxImageURLSize+1))
+	if err != nil {
+		return ContentPartImage{}, err
+	}
+	if len(data) > MaxImageURLSize {
+		return ContentPartImage{}, fmt.Errorf("%w: more than %d bytes", ErrImageTooLarge, MaxImageURLSize)
+	}
+
+	if mime == "" {
+		mime = http.DetectContentType(data)
+	}
+	mime = strings.TrimSpace(strings.SplitN(mime, ";", 2)[0])
+	if !supportedImageTypes[mime] {
+		return ContentPartImage{}, fmt.Errorf("%w: %s", ErrUnsupportedImageType, mime)
+	}
+
+	data, mime, err = fitImage(data, mime, o)
+	if err != nil {
+		return ContentPartImage{}, err
+	}
+
+	return ContentPartImage{
+		Type: "image_url",
+		ImageURL: ContentPartImage_ImageURL{
+			URL:    "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data),
+			Detail: o.detail,
+		},
+	}, nil
+}
+
+// ImageFromURL returns a content part referencing an http(s) image URL or a
+// base64 data URL
+func ImageFromURL(rawURL string, opts ...ImageOption) (ContentPartImage, error) {
+	o := imageOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if strings.HasPrefix(rawURL, "data:") {
+		header, payload, ok := strings.Cut(strings.TrimPrefix(rawURL, "data:"), ",")
+		if !ok || !strings.HasSuffix(header, ";base64") {
+			return ContentPartImage{}, errors.New("image data URL must be base64-encoded")
+		}
+		if mime := strings.TrimSuffix(header, ";base64"); !supportedImageTypes[mime] {
+			return ContentPartImage{}, fmt.Errorf("%w: %s", ErrUnsupportedImageType, mime)
+		}
+		if len(payload) > MaxImageBase64Size {
+			return ContentPartImage{}, fmt.Errorf("%w: base64 data is %d bytes, limit is %d", ErrImageTooLarge, len(payload), MaxImageBase64Size)
+		}
+	} else {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return ContentPartImage{}, fmt.Errorf("invalid image URL: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ContentPartImage{}, fmt.Errorf("invalid image URL %q: must be http(s) or a data URL", rawURL)
+		}
+	}
+
+	return ContentPartImage{
+		Type:     "image_url",
+		ImageURL: ContentPartImage_ImageURL{URL: rawURL, Detail: o.detail},
+	}, nil
+}
+
+// fitImage enforces the resolution and encoded size limits, downscaling
+// when the options allow it
+func fitImage(data []byte, mime string, o imageOptions) ([]byte, string, error) {
+	if mime == "image/webp" {
+		// The standard library cannot decode WebP, so only the size is checked
+		if base64.StdEncoding.EncodedLen(len(data)) > MaxImageBase64Size {
+			return nil, "", fmt.Errorf("%w: encoded size exceeds %d bytes", ErrImageTooLarge, MaxImageBase64Size)
+		}
+		if o.maxSide > 0 {
+			return nil, "", fmt.Errorf("%w: cannot resize %s", ErrUnsupportedImageType, mime)
+		}
+		return data, mime, nil
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image: %w", err)
+	}
+
+	w, h := cfg.Width, cfg.Height
+	if float64(w)*float64(h) > maxDecodePixels {
+		return nil, "", fmt.Errorf("%w: %dx%d exceeds the %d pixels that can be downscaled", ErrImageTooLarge, w, h, maxDecodePixels)
+	}
+	scale := 1.0
+	if o.maxSide > 0 && (w > o.maxSide || h > o.maxSide) {
+		scale = float64(o.maxSide) / float64(max(w, h))
+	}
+	if pixels := float64(w) * float64(h) * scale * scale; pixels > MaxImagePixels {
+		if !o.downscale {
+			return nil, "", fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrImageTooLarge, w, h, MaxImagePixels)
+		}
+		scale = math.Sqrt(MaxImagePixels / (float64(w) * float64(h)))
+	}
+	fitsSize := base64.StdEncoding.EncodedLen(len(data)) <= MaxImageBase64Size
+
+	if scale == 1 && fitsSize {
+		return data, mime, nil
+	}
+	if scale == 1 && !o.downscale {
+		return nil, "", fmt.Errorf("%w: encoded size exceeds %d bytes", ErrImageTooLarge, MaxImageBase64Size)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image: %w", err)
+	}
+
+	// Shrink further until the encoded image fits
+	for attempt := 0; attempt < 8; attempt++ {
+		dw := max(1, int(float64(w)*scale))
+		dh := max(1, int(float64(h)*scale))
+		out, outMime, err := encodeImage(resizeImage(src, dw, dh), mime)
+		if err != nil {
+			return nil, "", err
+		}
+		if base64.StdEncoding.EncodedLen(len(out)) <= MaxImageBase64Size {
+			return out, outMime, nil
+		}
+		if !o.downscale {
+			break
+		}
+		scale *= 0.75
+	}
+	return nil, "", fmt.Errorf("%w: encoded size exceeds %d bytes", ErrImageTooLarge, MaxImageBase64Size)
+}
+
+// encodeImage writes JPEG sources as JPEG and everything else as PNG
+func encodeImage(img image.Image, mime string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if mime == "image/jpeg" {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), mime, nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// resizeImage downscales src to w×h by averaging the source pixels covered
+// by each destination pixel
+func resizeImage(src image.Image, w, h int) image.Image {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	dst := image.NewRGBA64(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0 := sb.Min.Y + y*sh/h
+		y1 := max(y0+1, sb.Min.Y+(y+1)*sh/h)
+		for x := 0; x < w; x++ {
+			x0 := sb.Min.X + x*sw/w
+			x1 := max(x0+1, sb.Min.X+(x+1)*sw/w)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
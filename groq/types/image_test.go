@@ -0,0 +1,162 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withDimensions rewrites the PNG header to claim w×h pixels
+func withDimensions(data []byte, w, h uint32) []byte {
+	out := append([]byte(nil), data...)
+	binary.BigEndian.PutUint32(out[16:], w)
+	binary.BigEndian.PutUint32(out[20:], h)
+	binary.BigEndian.PutUint32(out[29:], crc32.ChecksumIEEE(out[12:29]))
+	return out
+}
+
+func decodeDataURL(t *testing.T, part ContentPartImage) (string, image.Config) {
+	t.Helper()
+	header, payload, ok := strings.Cut(strings.TrimPrefix(part.ImageURL.URL, "data:"), ",")
+	if !ok {
+		t.Fatalf("not a data URL: %.40s", part.ImageURL.URL)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatalf("invalid base64: %v", err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("DecodeConfig error: %v", err)
+	}
+	return strings.TrimSuffix(header, ";base64"), cfg
+}
+
+func TestImageFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.png")
+	if err := os.WriteFile(path, testPNG(t, 100, 50), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	part, err := ImageFromFile(path, ImageDetailHigh)
+	if err != nil {
+		t.Fatalf("ImageFromFile error: %v", err)
+	}
+	if part.Type != "image_url" || part.ImageURL.Detail != ImageDetailHigh {
+		t.Errorf("part = %+v", part)
+	}
+	mime, cfg := decodeDataURL(t, part)
+	if mime != "image/png" || cfg.Width != 100 || cfg.Height != 50 {
+		t.Errorf("got %s %dx%d, want image/png 100x50", mime, cfg.Width, cfg.Height)
+	}
+
+	if _, err := ImageFromFile(filepath.Join(t.TempDir(), "missing.png"), ""); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v, want not exist", err)
+	}
+}
+
+func TestImageFromReader(t *testing.T) {
+	t.Run("max side", func(t *testing.T) {
+		part, err := ImageFromReader(bytes.NewReader(testPNG(t, 100, 50)), "", WithImageMaxSide(20))
+		if err != nil {
+			t.Fatalf("ImageFromReader error: %v", err)
+		}
+		_, cfg := decodeDataURL(t, part)
+		if cfg.Width != 20 || cfg.Height != 10 {
+			t.Errorf("got %dx%d, want 20x10", cfg.Width, cfg.Height)
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := ImageFromReader(strings.NewReader("just some text"), "")
+		if !errors.Is(err, ErrUnsupportedImageType) {
+			t.Errorf("error = %v, want ErrUnsupportedImageType", err)
+		}
+		_, err = ImageFromReader(bytes.NewReader(testPNG(t, 2, 2)), "image/tiff")
+		if !errors.Is(err, ErrUnsupportedImageType) {
+			t.Errorf("error = %v, want ErrUnsupportedImageType", err)
+		}
+	})
+
+	t.Run("resolution limit", func(t *testing.T) {
+		huge := withDimensions(testPNG(t, 2, 2), 8000, 8000)
+		_, err := ImageFromReader(bytes.NewReader(huge), "")
+		if !errors.Is(err, ErrImageTooLarge) || !strings.Contains(err.Error(), "8000x8000") {
+			t.Errorf("error = %v, want ErrImageTooLarge for 8000x8000", err)
+		}
+	})
+
+	t.Run("decode limit", func(t *testing.T) {
+		forged := withDimensions(testPNG(t, 2, 2), 100_000, 100_000)
+		_, err := ImageFromReader(bytes.NewReader(forged), "", WithImageDownscale())
+		if !errors.Is(err, ErrImageTooLarge) || !strings.Contains(err.Error(), "100000x100000") {
+			t.Errorf("error = %v, want ErrImageTooLarge for 100000x100000", err)
+		}
+	})
+
+	t.Run("byte limit", func(t *testing.T) {
+		data := make([]byte, MaxImageURLSize+1)
+		copy(data, testPNG(t, 2, 2))
+		_, err := ImageFromReader(bytes.NewReader(data), "image/png")
+		if !errors.Is(err, ErrImageTooLarge) {
+			t.Errorf("error = %v, want ErrImageTooLarge", err)
+		}
+	})
+}
+
+func TestImageFromURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "https", url: "https://example.com/cat.jpg"},
+		{name: "data URL", url: "data:image/png;base64,iVBORw0KGgo="},
+		{name: "ftp", url: "ftp://example.com/cat.jpg", wantErr: errors.New("must be http(s) or a data URL")},
+		{name: "relative", url: "cat.jpg", wantErr: errors.New("must be http(s) or a data URL")},
+		{name: "data URL type", url: "data:image/tiff;base64,AAAA", wantErr: ErrUnsupportedImageType},
+		{name: "data URL encoding", url: "data:image/png,rawbytes", wantErr: errors.New("must be base64-encoded")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			part, err := ImageFromURL(tt.url, WithImageDetail(ImageDetailLow))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if part.ImageURL.URL != tt.url || part.ImageURL.Detail != ImageDetailLow {
+					t.Errorf("part = %+v", part)
+				}
+				return
+			}
+			if err == nil || (!errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error())) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
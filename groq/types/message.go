@@ -0,0 +1,154 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMessage is wrapped by errors for messages whose content does
+// not fit their role
+var ErrInvalidMessage = errors.New("invalid message")
+
+// MarshalJSON checks that the content fits the role before encoding
+func (m ChatCompletionMessageParam) MarshalJSON() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	type alias ChatCompletionMessageParam
+	return json.Marshal(alias(m))
+}
+
+// UnmarshalJSON decodes content into a string or a []ContentPart holding
+// ContentPartText, ContentPartImage and ContentPartDocument values, and
+// rejects content that does not fit the role
+func (m *ChatCompletionMessageParam) UnmarshalJSON(data []byte) error {
+	type alias ChatCompletionMessageParam
+	var raw struct {
+		alias
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	msg := ChatCompletionMessageParam(raw.alias)
+	content, err := decodeContent(raw.Content)
+	if err != nil {
+		return fmt.Errorf("%w: %s message: %v", ErrInvalidMessage, msg.Role, err)
+	}
+	msg.Content = content
+
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	*m = msg
+	return nil
+}
+
+func decodeContent(data json.RawMessage) (interface{}, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	switch data[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case '[':
+		var raws []json.RawMessage
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, err
+		}
+		parts := make([]ContentPart, len(raws))
+		for i, r := range raws {
+			part, err := UnmarshalContentPart(r)
+			if err != nil {
+				return nil, fmt.Errorf("content part %d: %w", i, err)
+			}
+			parts[i] = part
+		}
+		return parts, nil
+	}
+	return nil, errors.New("content must be a string or an array of content parts")
+}
+
+// Validate reports whether the content and fields of m are allowed for its
+// role: system, tool and function messages take text only, images and
+// documents are limited to user messages, tool calls to assistant messages,
+// and tool messages need a ToolCallID.
+func (m ChatCompletionMessageParam) Validate() error {
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s message: %s", ErrInvalidMessage, m.Role, fmt.Sprintf(format, args...))
+	}
+
+	switch m.Role {
+	case RoleSystem, RoleUser, RoleAssistant, RoleTool, RoleFunction:
+	case "":
+		return fmt.Errorf("%w: role is required", ErrInvalidMessage)
+	default:
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidMessage, m.Role)
+	}
+
+	if len(m.ToolCalls) > 0 && m.Role != RoleAssistant {
+		return fail("tool_calls are only allowed in assistant messages")
+	}
+	if m.ToolCallID != "" && m.Role != RoleTool {
+		return fail("tool_call_id is only allowed in tool messages")
+	}
+	if m.Role == RoleTool && m.ToolCallID == "" {
+		return fail("tool_call_id is required")
+	}
+
+	if m.Content == nil {
+		if m.Role == RoleAssistant {
+			return nil // Content may be null alongside tool calls
+		}
+		return fail("content is required")
+	}
+
+	var parts []ContentPart
+	switch c := m.Content.(type) {
+	case string:
+		return nil
+	case []ContentPart:
+		parts = c
+	case []interface{}:
+		for _, p := range c {
+			if part, ok := p.(ContentPart); ok {
+				parts = append(parts, part)
+			}
+		}
+	default:
+		return nil // Other encodings are passed through as is
+	}
+
+	for _, part := range parts {
+		switch part.(type) {
+		case ContentPartText, *ContentPartText:
+		case ContentPartImage, *ContentPartImage, ContentPartDocument, *ContentPartDocument:
+			if m.Role != RoleUser {
+				return fail("%s content parts are only allowed in user messages", contentPartType(part))
+			}
+		}
+	}
+	if m.Role == RoleFunction && len(parts) > 0 {
+		return fail("content must be a string")
+	}
+	return nil
+}
+
+func contentPartType(part ContentPart) string {
+	switch part.(type) {
+	case ContentPartImage, *ContentPartImage:
+		return "image_url"
+	case ContentPartDocument, *ContentPartDocument:
+		return "document"
+	}
+	return "text"
+}
@@ -0,0 +1,123 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChatCompletionMessageParam_RoundTrip(t *testing.T) {
+	msgs := []ChatCompletionMessageParam{
+		{Role: RoleSystem, Content: "Be brief."},
+		{Role: RoleUser, Content: []ContentPart{
+			ContentPartText{Type: "text", Text: "What is this?"},
+			ContentPartImage{Type: "image_url", ImageURL: ContentPartImage_ImageURL{URL: "https://example.com/a.png", Detail: "low"}},
+		}},
+		{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "f", Arguments: "{}"}}}},
+		{Role: RoleTool, Content: "42", ToolCallID: "call_1"},
+	}
+
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var decoded []ChatCompletionMessageParam
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, msgs) {
+		t.Errorf("round trip mismatch:\ngot  %#v\nwant %#v", decoded, msgs)
+	}
+
+	if !strings.Contains(string(data), `{"role":"assistant","content":null,"tool_calls"`) {
+		t.Errorf("assistant tool call message encoded as %s", data)
+	}
+}
+
+func TestContentPart_DefaultType(t *testing.T) {
+	msg := ChatCompletionMessageParam{Role: RoleUser, Content: []ContentPart{ContentPartText{Text: "hi"}}}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	expected := `{"role":"user","content":[{"type":"text","text":"hi"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, string(data))
+	}
+}
+
+func TestChatCompletionMessageParam_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		msg     *ChatCompletionMessageParam
+		wantErr string
+	}{
+		{
+			name:    "image in system message",
+			json:    `{"role":"system","content":[{"type":"image_url","image_url":{"url":"https://x"}}]}`,
+			wantErr: "image_url content parts are only allowed in user messages",
+		},
+		{
+			name:    "document in assistant message",
+			msg:     &ChatCompletionMessageParam{Role: RoleAssistant, Content: []ContentPart{ContentPartDocument{}}},
+			wantErr: "document content parts are only allowed in user messages",
+		},
+		{
+			name:    "unknown part type",
+			json:    `{"role":"user","content":[{"type":"video","video":{}}]}`,
+			wantErr: `content part 0: unknown content part type "video"`,
+		},
+		{
+			name:    "object content",
+			json:    `{"role":"user","content":{"text":"hi"}}`,
+			wantErr: "content must be a string or an array of content parts",
+		},
+		{
+			name:    "tool without call id",
+			json:    `{"role":"tool","content":"42"}`,
+			wantErr: "tool_call_id is required",
+		},
+		{
+			name:    "tool calls on user message",
+			msg:     &ChatCompletionMessageParam{Role: RoleUser, Content: "hi", ToolCalls: []ToolCall{{ID: "x"}}},
+			wantErr: "tool_calls are only allowed in assistant messages",
+		},
+		{
+			name:    "missing user content",
+			json:    `{"role":"user"}`,
+			wantErr: "content is required",
+		},
+		{
+			name:    "unknown role",
+			json:    `{"role":"developer","content":"hi"}`,
+			wantErr: `unknown role "developer"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.msg != nil {
+				_, err = json.Marshal(tt.msg)
+			} else {
+				var msg ChatCompletionMessageParam
+				err = json.Unmarshal([]byte(tt.json), &msg)
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("error %v does not wrap ErrInvalidMessage", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package types
 
 // Model represents a model
 type Model struct {
-	ID      string `json:"id"`
-	Created int64  `json:"created"`
-	Object  string `json:"object"`
-	OwnedBy string `json:"owned_by"`
+	ID                  string      `json:"id"`
+	Created             int64       `json:"created"`
+	Object              string      `json:"object"`
+	OwnedBy             string      `json:"owned_by"`
+	Active              bool        `json:"active"`
+	ContextWindow       int         `json:"context_window,omitempty"`        // In tokens
+	MaxCompletionTokens int         `json:"max_completion_tokens,omitempty"` // In tokens
+	PublicApps          interface{} `json:"public_apps,omitempty"`           // Usually null
 }
 
 // ModelListResponse represents a list of models
@@ -0,0 +1,31 @@
+package types
+
+import "github.com/ZaguanLabs/groq-go/groq/jsonschema"
+
+// NewFunctionTool returns a function tool whose parameters are the JSON
+// Schema of T, generated by the jsonschema package. It panics if T cannot be
+// described by a strict schema, such as a map, channel or func type.
+func NewFunctionTool[T any](name, description string) ChatCompletionTool {
+	return ChatCompletionTool{
+		Type: "function",
+		Function: FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters:  mustSchema[T](),
+		},
+	}
+}
+
+// JSONSchemaFormat returns a strict json_schema response format for T. It
+// panics if T cannot be described by a schema.
+func JSONSchemaFormat[T any](name string) *ResponseFormat {
+	return NewJSONSchemaFormat(name, mustSchema[T](), true)
+}
+
+func mustSchema[T any]() jsonschema.Schema {
+	schema, err := jsonschema.For[T]()
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type getWeatherParams struct {
+	City string `json:"city" description:"City name"`
+}
+
+func TestNewFunctionTool(t *testing.T) {
+	tool := NewFunctionTool[getWeatherParams]("get_weather", "Get the weather")
+
+	b, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	expected := `{"type":"function","function":{"name":"get_weather","description":"Get the weather","parameters":{"additionalProperties":false,"properties":{"city":{"description":"City name","type":"string"}},"required":["city"],"type":"object"}}}`
+	if string(b) != expected {
+		t.Errorf("Expected %s, got %s", expected, string(b))
+	}
+}
+
+func TestJSONSchemaFormat(t *testing.T) {
+	format := JSONSchemaFormat[getWeatherParams]("weather")
+
+	if format.Type != "json_schema" || format.JSONSchema.Name != "weather" {
+		t.Errorf("format = %+v", format)
+	}
+	if format.JSONSchema.Strict == nil || !*format.JSONSchema.Strict {
+		t.Error("Strict not enabled")
+	}
+	if format.JSONSchema.Schema["type"] != "object" {
+		t.Errorf("schema = %v", format.JSONSchema.Schema)
+	}
+}
+
+func TestNewFunctionTool_Unsupported(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(error).Error(), "unsupported type") {
+			t.Errorf("recover() = %v, want unsupported type panic", r)
+		}
+	}()
+	NewFunctionTool[chan int]("bad", "")
+}
@@ -2,10 +2,16 @@ package types
 
 // ErrorObject represents an error returned by the API
 type ErrorObject struct {
-	Message string      `json:"message"`
-	Type    string      `json:"type"`
-	Param   interface{} `json:"param,omitempty"`
-	Code    interface{} `json:"code,omitempty"`
+	Message          string      `json:"message"`
+	Type             string      `json:"type"`
+	Param            interface{} `json:"param,omitempty"`
+	Code             interface{} `json:"code,omitempty"`
+	FailedGeneration string      `json:"failed_generation,omitempty"` // Model output that failed validation (json_validate_failed, tool_use_failed)
+}
+
+// ErrorResponse is the envelope the API wraps errors in
+type ErrorResponse struct {
+	Error *ErrorObject `json:"error"`
 }
 
 // FunctionDefinition represents a function definition
@@ -0,0 +1,267 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MaxStopSequences is the number of stop sequences the API accepts
+const MaxStopSequences = 4
+
+// ToolChoice controls whether and which tool the model calls. Build one
+// with ToolChoiceNone, ToolChoiceAuto, ToolChoiceRequired or
+// ToolChoiceFunction.
+type ToolChoice struct {
+	mode     string // "none", "auto" or "required"
+	function string // Set instead of mode to force a function
+}
+
+// ToolChoiceNone stops the model from calling tools
+func ToolChoiceNone() *ToolChoice { return &ToolChoice{mode: "none"} }
+
+// ToolChoiceAuto lets the model decide whether to call tools
+func ToolChoiceAuto() *ToolChoice { return &ToolChoice{mode: "auto"} }
+
+// ToolChoiceRequired makes the model call at least one tool
+func ToolChoiceRequired() *ToolChoice { return &ToolChoice{mode: "required"} }
+
+// ToolChoiceFunction forces the model to call the named function
+func ToolChoiceFunction(name string) *ToolChoice { return &ToolChoice{function: name} }
+
+// Mode returns "none", "auto" or "required", or "" for a named function
+func (c ToolChoice) Mode() string { return c.mode }
+
+// FunctionName returns the forced function, or "" for a mode
+func (c ToolChoice) FunctionName() string { return c.function }
+
+type toolChoiceFunction struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+// MarshalJSON encodes a mode as a string and a function as an object
+func (c ToolChoice) MarshalJSON() ([]byte, error) {
+	if c.function != "" {
+		var v toolChoiceFunction
+		v.Type = "function"
+		v.Function.Name = c.function
+		return json.Marshal(v)
+	}
+	if !isToolChoiceMode(c.mode) {
+		return nil, errors.New("types: empty ToolChoice; use ToolChoiceAuto, ToolChoiceRequired, ToolChoiceNone or ToolChoiceFunction")
+	}
+	return json.Marshal(c.mode)
+}
+
+// UnmarshalJSON accepts "none", "auto", "required" or a function object
+func (c *ToolChoice) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var mode string
+		if err := json.Unmarshal(data, &mode); err != nil {
+			return err
+		}
+		if !isToolChoiceMode(mode) {
+			return fmt.Errorf("types: invalid tool_choice %q", mode)
+		}
+		*c = ToolChoice{mode: mode}
+		return nil
+	}
+
+	var v toolChoiceFunction
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("types: invalid tool_choice: %w", err)
+	}
+	if v.Type != "function" || v.Function.Name == "" {
+		return fmt.Errorf("types: invalid tool_choice %s", data)
+	}
+	*c = ToolChoice{function: v.Function.Name}
+	return nil
+}
+
+func isToolChoiceMode(mode string) bool {
+	return mode == "none" || mode == "auto" || mode == "required"
+}
+
+// FunctionCallChoice is the deprecated counterpart of ToolChoice for
+// CreateChatCompletionRequest.FunctionCall
+type FunctionCallChoice struct {
+	mode string // "none" or "auto"
+	name string
+}
+
+// FunctionCallNone stops the model from calling functions
+func FunctionCallNone() *FunctionCallChoice { return &FunctionCallChoice{mode: "none"} }
+
+// FunctionCallAuto lets the model decide whether to call a function
+func FunctionCallAuto() *FunctionCallChoice { return &FunctionCallChoice{mode: "auto"} }
+
+// FunctionCallName forces the model to call the named function
+func FunctionCallName(name string) *FunctionCallChoice { return &FunctionCallChoice{name: name} }
+
+// Mode returns "none" or "auto", or "" for a named function
+func (c FunctionCallChoice) Mode() string { return c.mode }
+
+// FunctionName returns the forced function, or "" for a mode
+func (c FunctionCallChoice) FunctionName() string { return c.name }
+
+// MarshalJSON encodes a mode as a string and a function as {"name": ...}
+func (c FunctionCallChoice) MarshalJSON() ([]byte, error) {
+	if c.name != "" {
+		return json.Marshal(map[string]string{"name": c.name})
+	}
+	if c.mode != "none" && c.mode != "auto" {
+		return nil, errors.New("types: empty FunctionCallChoice; use FunctionCallNone, FunctionCallAuto or FunctionCallName")
+	}
+	return json.Marshal(c.mode)
+}
+
+// UnmarshalJSON accepts "none", "auto" or {"name": ...}
+func (c *FunctionCallChoice) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var mode string
+		if err := json.Unmarshal(data, &mode); err != nil {
+			return err
+		}
+		if mode != "none" && mode != "auto" {
+			return fmt.Errorf("types: invalid function_call %q", mode)
+		}
+		*c = FunctionCallChoice{mode: mode}
+		return nil
+	}
+
+	var v struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("types: invalid function_call: %w", err)
+	}
+	if v.Name == "" {
+		return fmt.Errorf("types: invalid function_call %s", data)
+	}
+	*c = FunctionCallChoice{name: v.Name}
+	return nil
+}
+
+// Stop holds up to MaxStopSequences sequences at which the model stops
+// generating. Build one with StopSequence or StopSequences.
+type Stop struct {
+	sequences []string
+	single    bool // Encoded as a string rather than an array
+}
+
+// StopSequence stops generation at s; it is sent as a single string
+func StopSequence(s string) *Stop { return &Stop{sequences: []string{s}, single: true} }
+
+// StopSequences stops generation at any of seqs; they are sent as an array
+func StopSequences(seqs ...string) *Stop {
+	return &Stop{sequences: append([]string(nil), seqs...)}
+}
+
+// Sequences returns the stop sequences
+func (s Stop) Sequences() []string { return append([]string(nil), s.sequences...) }
+
+// MarshalJSON encodes a single sequence as a string and several as an array
+func (s Stop) MarshalJSON() ([]byte, error) {
+	if err := validateStop(s.sequences); err != nil {
+		return nil, err
+	}
+	if s.single {
+		return json.Marshal(s.sequences[0])
+	}
+	return json.Marshal(s.sequences)
+}
+
+// UnmarshalJSON accepts a string or an array of strings
+func (s *Stop) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	var out Stop
+	if len(data) > 0 && data[0] == '"' {
+		var seq string
+		if err := json.Unmarshal(data, &seq); err != nil {
+			return err
+		}
+		out = Stop{sequences: []string{seq}, single: true}
+	} else if err := json.Unmarshal(data, &out.sequences); err != nil {
+		return fmt.Errorf("types: stop must be a string or an array of strings: %w", err)
+	}
+
+	if err := validateStop(out.sequences); err != nil {
+		return err
+	}
+	*s = out
+	return nil
+}
+
+func validateStop(seqs []string) error {
+	if len(seqs) == 0 {
+		return errors.New("types: stop needs at least one sequence")
+	}
+	if len(seqs) > MaxStopSequences {
+		return fmt.Errorf("types: stop accepts at most %d sequences, got %d", MaxStopSequences, len(seqs))
+	}
+	return nil
+}
+
+// ResponseFormatText returns the default plain text response format
+func ResponseFormatText() *ResponseFormat { return &ResponseFormat{Type: "text"} }
+
+// ResponseFormatJSONObject returns the JSON mode response format
+func ResponseFormatJSONObject() *ResponseFormat { return &ResponseFormat{Type: "json_object"} }
+
+// NewJSONSchemaFormat returns a json_schema response format for schema.
+// See JSONSchemaFormat to derive the schema from a Go type.
+func NewJSONSchemaFormat(name string, schema map[string]interface{}, strict bool) *ResponseFormat {
+	return &ResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &ResponseFormatJSONSchema{
+			Name:   name,
+			Schema: schema,
+			Strict: &strict,
+		},
+	}
+}
+
+// MarshalJSON checks that JSONSchema is set exactly for the json_schema type
+func (f ResponseFormat) MarshalJSON() ([]byte, error) {
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	type alias ResponseFormat
+	return json.Marshal(alias(f))
+}
+
+// UnmarshalJSON rejects unknown types and a missing json_schema
+func (f *ResponseFormat) UnmarshalJSON(data []byte) error {
+	type alias ResponseFormat
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := ResponseFormat(v).validate(); err != nil {
+		return err
+	}
+	*f = ResponseFormat(v)
+	return nil
+}
+
+func (f ResponseFormat) validate() error {
+	switch f.Type {
+	case "text", "json_object":
+		if f.JSONSchema != nil {
+			return fmt.Errorf("types: response_format %q does not take json_schema", f.Type)
+		}
+	case "json_schema":
+		if f.JSONSchema == nil || f.JSONSchema.Name == "" {
+			return errors.New("types: response_format json_schema needs json_schema with a name")
+		}
+	default:
+		return fmt.Errorf("types: invalid response_format type %q", f.Type)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnions_Marshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"tool choice none", ToolChoiceNone(), `"none"`},
+		{"tool choice auto", ToolChoiceAuto(), `"auto"`},
+		{"tool choice required", ToolChoiceRequired(), `"required"`},
+		{"tool choice function", ToolChoiceFunction("get_weather"), `{"type":"function","function":{"name":"get_weather"}}`},
+		{"function call auto", FunctionCallAuto(), `"auto"`},
+		{"function call name", FunctionCallName("get_weather"), `{"name":"get_weather"}`},
+		{"stop sequence", StopSequence("\n"), `"\n"`},
+		{"stop sequences", StopSequences("END", "STOP"), `["END","STOP"]`},
+		{"stop single array", StopSequences("END"), `["END"]`},
+		{"response format text", ResponseFormatText(), `{"type":"text"}`},
+		{"response format json object", ResponseFormatJSONObject(), `{"type":"json_object"}`},
+		{"response format json schema", NewJSONSchemaFormat("out", map[string]interface{}{"type": "object"}, true), `{"type":"json_schema","json_schema":{"name":"out","schema":{"type":"object"},"strict":true}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+
+			// Decoding the output must yield the same value
+			decoded := reflect.New(reflect.TypeOf(tt.value).Elem()).Interface()
+			if err := json.Unmarshal(b, decoded); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.value) {
+				t.Errorf("round trip = %+v, want %+v", decoded, tt.value)
+			}
+		})
+	}
+}
+
+func TestUnions_MarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"zero tool choice", &ToolChoice{}},
+		{"zero function call", &FunctionCallChoice{}},
+		{"no stop sequences", StopSequences()},
+		{"too many stop sequences", StopSequences("a", "b", "c", "d", "e")},
+		{"unknown response format", &ResponseFormat{Type: "xml"}},
+		{"json schema without schema", &ResponseFormat{Type: "json_schema"}},
+		{"json object with schema", &ResponseFormat{Type: "json_object", JSONSchema: &ResponseFormatJSONSchema{Name: "x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if b, err := json.Marshal(tt.value); err == nil {
+				t.Errorf("expected error, got %s", b)
+			}
+		})
+	}
+}
+
+func TestUnions_UnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		target interface{}
+	}{
+		{"unknown tool choice", `"sometimes"`, new(ToolChoice)},
+		{"tool choice without name", `{"type":"function","function":{}}`, new(ToolChoice)},
+		{"tool choice wrong type", `{"type":"retrieval","function":{"name":"x"}}`, new(ToolChoice)},
+		{"tool choice number", `1`, new(ToolChoice)},
+		{"function call required", `"required"`, new(FunctionCallChoice)},
+		{"function call without name", `{}`, new(FunctionCallChoice)},
+		{"stop number", `42`, new(Stop)},
+		{"stop empty array", `[]`, new(Stop)},
+		{"stop too many", `["a","b","c","d","e"]`, new(Stop)},
+		{"response format unknown", `{"type":"yaml"}`, new(ResponseFormat)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.data), tt.target); err == nil {
+				t.Errorf("expected error for %s", tt.data)
+			}
+		})
+	}
+}
+
+func TestChatCompletionRequest_Unions(t *testing.T) {
+	data := `{"messages":[{"role":"user","content":"Hi"}],"model":"m","response_format":{"type":"json_object"},"stop":["END"],"tool_choice":{"type":"function","function":{"name":"lookup"}}}`
+
+	var req CreateChatCompletionRequest
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if req.ToolChoice.FunctionName() != "lookup" || req.ToolChoice.Mode() != "" {
+		t.Errorf("tool choice = %+v", req.ToolChoice)
+	}
+	if got := req.Stop.Sequences(); len(got) != 1 || got[0] != "END" {
+		t.Errorf("stop = %q", got)
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if string(b) != data {
+		t.Errorf("got %s, want %s", b, data)
+	}
+}
@@ -0,0 +1,286 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+)
+
+// FieldError reports a request field that fails client-side validation
+type FieldError struct {
+	Field   string // Path of the field, e.g. "tools[1].function.name"
+	Message string
+	Err     error // Underlying error, if any
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// validName matches function, tool and json_schema names
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// batchEndpoints are the endpoints a batch can target
+var batchEndpoints = map[string]bool{
+	"/v1/chat/completions":     true,
+	"/v1/embeddings":           true,
+	"/v1/audio/transcriptions": true,
+	"/v1/audio/translations":   true,
+}
+
+// Batch completion window bounds
+const (
+	minCompletionWindowHours = 24
+	maxCompletionWindowHours = 7 * 24
+)
+
+func fieldErr(field, format string, args ...interface{}) *FieldError {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func checkRange[T int | float64](field string, o *option.Optional[T], min, max T) error {
+	if o != nil && o.Set && (o.Value < min || o.Value > max) {
+		return fieldErr(field, "%v is out of range [%v, %v]", o.Value, min, max)
+	}
+	return nil
+}
+
+// Validate checks the request before it is sent: required fields, value
+// ranges, mutually exclusive fields, tool and schema names, and each message.
+// It returns a *FieldError for the first problem found.
+func (r CreateChatCompletionRequest) Validate() error {
+	if r.Model == "" {
+		return fieldErr("model", "is required")
+	}
+	if len(r.Messages) == 0 {
+		return fieldErr("messages", "at least one message is required")
+	}
+	for i, m := range r.Messages {
+		if err := m.Validate(); err != nil {
+			return &FieldError{Field: "messages[" + strconv.Itoa(i) + "]", Message: err.Error(), Err: err}
+		}
+	}
+
+	if err := checkRange("temperature", r.Temperature, 0, 2); err != nil {
+		return err
+	}
+	if err := checkRange("top_p", r.TopP, 0, 1); err != nil {
+		return err
+	}
+	if err := checkRange("frequency_penalty", r.FrequencyPenalty, -2, 2); err != nil {
+		return err
+	}
+	if err := checkRange("presence_penalty", r.PresencePenalty, -2, 2); err != nil {
+		return err
+	}
+	if r.N != nil && r.N.Set && r.N.Value < 1 {
+		return fieldErr("n", "must be at least 1, got %d", r.N.Value)
+	}
+	if r.TopLogprobs != nil && r.TopLogprobs.Set {
+		if err := checkRange("top_logprobs", r.TopLogprobs, 0, 20); err != nil {
+			return err
+		}
+		if r.Logprobs == nil || !r.Logprobs.Set || !r.Logprobs.Value {
+			return fieldErr("top_logprobs", "requires logprobs to be true")
+		}
+	}
+
+	maxTokensSet := r.MaxTokens != nil && r.MaxTokens.Set
+	if maxTokensSet && r.MaxCompletionTokens != nil && r.MaxCompletionTokens.Set {
+		return fieldErr("max_tokens", "cannot be combined with max_completion_tokens")
+	}
+	if maxTokensSet && r.MaxTokens.Value < 1 {
+		return fieldErr("max_tokens", "must be at least 1, got %d", r.MaxTokens.Value)
+	}
+	if r.MaxCompletionTokens != nil && r.MaxCompletionTokens.Set && r.MaxCompletionTokens.Value < 1 {
+		return fieldErr("max_completion_tokens", "must be at least 1, got %d", r.MaxCompletionTokens.Value)
+	}
+
+	if len(r.Functions) > 0 && len(r.Tools) > 0 {
+		return fieldErr("functions", "cannot be combined with tools; use tools only")
+	}
+	if r.FunctionCall != nil && r.ToolChoice != nil {
+		return fieldErr("function_call", "cannot be combined with tool_choice; use tool_choice only")
+	}
+	if r.FunctionCall != nil && len(r.Functions) == 0 {
+		return fieldErr("function_call", "requires functions")
+	}
+
+	toolNames := make(map[string]bool, len(r.Tools))
+	for i, tool := range r.Tools {
+		if tool.Type != "function" {
+			continue // Built-in tools carry no function definition
+		}
+		field := "tools[" + strconv.Itoa(i) + "].function.name"
+		if err := checkName(field, tool.Function.Name); err != nil {
+			return err
+		}
+		if toolNames[tool.Function.Name] {
+			return fieldErr(field, "duplicate tool name %q", tool.Function.Name)
+		}
+		toolNames[tool.Function.Name] = true
+	}
+	for i, fn := range r.Functions {
+		if err := checkName("functions["+strconv.Itoa(i)+"].name", fn.Name); err != nil {
+			return err
+		}
+	}
+
+	if tc := r.ToolChoice; tc != nil {
+		if tc.Mode() == "required" && len(r.Tools) == 0 {
+			return fieldErr("tool_choice", `"required" needs at least one tool`)
+		}
+		if name := tc.FunctionName(); name != "" && !toolNames[name] {
+			return fieldErr("tool_choice", "function %q is not in tools", name)
+		}
+	}
+
+	if f := r.ResponseFormat; f != nil {
+		if err := f.validate(); err != nil {
+			return &FieldError{Field: "response_format", Message: strings.TrimPrefix(err.Error(), "types: "), Err: err}
+		}
+		if f.JSONSchema != nil {
+			if err := checkName("response_format.json_schema.name", f.JSONSchema.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.Stop != nil {
+		if err := validateStop(r.Stop.sequences); err != nil {
+			return &FieldError{Field: "stop", Message: strings.TrimPrefix(err.Error(), "types: "), Err: err}
+		}
+	}
+	return nil
+}
+
+func checkName(field, name string) error {
+	if !validName.MatchString(name) {
+		return fieldErr(field, "%q must be 1-64 characters of a-z, A-Z, 0-9, underscores and dashes", name)
+	}
+	return nil
+}
+
+// Speech output formats and sample rates
+var (
+	speechFormats     = []string{"flac", "mp3", "mulaw", "ogg", "wav"}
+	speechSampleRates = []int{8000, 16000, 22050, 24000, 32000, 44100, 48000}
+)
+
+// Validate checks the required fields and the format, sample rate and
+// speed. It returns a *FieldError for the first problem found.
+func (r CreateSpeechRequest) Validate() error {
+	switch {
+	case r.Model == "":
+		return fieldErr("model", "is required")
+	case r.Input == "":
+		return fieldErr("input", "is required")
+	case r.Voice == "":
+		return fieldErr("voice", "is required")
+	}
+	if r.ResponseFormat != nil && r.ResponseFormat.Set && !oneOf(r.ResponseFormat.Value, speechFormats) {
+		return fieldErr("response_format", "%q is not one of %s", r.ResponseFormat.Value, strings.Join(speechFormats, ", "))
+	}
+	if r.SampleRate != nil && r.SampleRate.Set && !oneOf(r.SampleRate.Value, speechSampleRates) {
+		return fieldErr("sample_rate", "%d is not a supported sample rate", r.SampleRate.Value)
+	}
+	return checkRange("speed", r.Speed, 0.5, 5)
+}
+
+// Transcription response formats and timestamp granularities
+var (
+	transcriptionFormats   = []string{"json", "text", "verbose_json"}
+	timestampGranularities = []string{"word", "segment"}
+)
+
+// Validate checks that exactly one of File and URL is set, and the
+// temperature, format and timestamp granularities. It returns a *FieldError
+// for the first problem found.
+func (r CreateTranscriptionRequest) Validate() error {
+	if r.Model == "" {
+		return fieldErr("model", "is required")
+	}
+	hasURL := r.URL != nil && r.URL.Set && r.URL.Value != ""
+	if r.File == nil && !hasURL {
+		return fieldErr("file", "file or url is required")
+	}
+	if r.File != nil && hasURL {
+		return fieldErr("url", "cannot be combined with file")
+	}
+	if err := checkRange("temperature", r.Temperature, 0, 1); err != nil {
+		return err
+	}
+
+	format := "json"
+	if r.ResponseFormat != nil && r.ResponseFormat.Set {
+		format = r.ResponseFormat.Value
+		if !oneOf(format, transcriptionFormats) {
+			return fieldErr("response_format", "%q is not one of %s", format, strings.Join(transcriptionFormats, ", "))
+		}
+	}
+	for i, g := range r.TimestampGranularities {
+		field := "timestamp_granularities[" + strconv.Itoa(i) + "]"
+		if !oneOf(g, timestampGranularities) {
+			return fieldErr(field, "%q is not one of %s", g, strings.Join(timestampGranularities, ", "))
+		}
+		if format != "verbose_json" {
+			return fieldErr(field, "requires response_format verbose_json")
+		}
+	}
+	return nil
+}
+
+// Validate checks the input file, endpoint and completion window, which
+// must be between 24h and 7d. It returns a *FieldError for the first problem
+// found.
+func (r CreateBatchRequest) Validate() error {
+	if r.InputFileID == "" {
+		return fieldErr("input_file_id", "is required")
+	}
+	if !batchEndpoints[r.Endpoint] {
+		return fieldErr("endpoint", "%q is not a batch endpoint", r.Endpoint)
+	}
+
+	hours, ok := completionWindowHours(r.CompletionWindow)
+	if !ok {
+		return fieldErr("completion_window", "%q must be a duration in hours or days, such as 24h or 7d", r.CompletionWindow)
+	}
+	if hours < minCompletionWindowHours || hours > maxCompletionWindowHours {
+		return fieldErr("completion_window", "%q must be between 24h and 7d", r.CompletionWindow)
+	}
+	return nil
+}
+
+// completionWindowHours parses windows such as "24h" and "7d"
+func completionWindowHours(window string) (int, bool) {
+	if len(window) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(window[:len(window)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	switch window[len(window)-1] {
+	case 'h':
+		return n, true
+	case 'd':
+		return n * 24, true
+	}
+	return 0, false
+}
+
+func oneOf[T comparable](v T, allowed []T) bool {
+	for _, a := range allowed {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
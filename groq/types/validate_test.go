@@ -0,0 +1,206 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+)
+
+func validChatRequest() CreateChatCompletionRequest {
+	return CreateChatCompletionRequest{
+		Model:    "llama-3.3-70b-versatile",
+		Messages: []ChatCompletionMessageParam{{Role: RoleUser, Content: "Hi"}},
+	}
+}
+
+func weatherTool(name string) ChatCompletionTool {
+	return ChatCompletionTool{Type: "function", Function: FunctionDefinition{Name: name}}
+}
+
+func TestCreateChatCompletionRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(r *CreateChatCompletionRequest)
+		wantField string
+	}{
+		{"valid", func(r *CreateChatCompletionRequest) {}, ""},
+		{"valid with options", func(r *CreateChatCompletionRequest) {
+			r.Temperature = option.Ptr(option.Some(2.0))
+			r.TopP = option.Ptr(option.Some(0.0))
+			r.Logprobs = option.Ptr(option.Some(true))
+			r.TopLogprobs = option.Ptr(option.Some(20))
+			r.MaxCompletionTokens = option.Ptr(option.Some(100))
+			r.Tools = []ChatCompletionTool{weatherTool("get_weather"), {Type: "browser_search"}}
+			r.ToolChoice = ToolChoiceFunction("get_weather")
+			r.ResponseFormat = NewJSONSchemaFormat("weather-report", map[string]interface{}{"type": "object"}, true)
+			r.Stop = StopSequences("END")
+		}, ""},
+		{"missing model", func(r *CreateChatCompletionRequest) { r.Model = "" }, "model"},
+		{"no messages", func(r *CreateChatCompletionRequest) { r.Messages = nil }, "messages"},
+		{"invalid message", func(r *CreateChatCompletionRequest) {
+			r.Messages = append(r.Messages, ChatCompletionMessageParam{Role: RoleTool, Content: "42"})
+		}, "messages[1]"},
+		{"temperature", func(r *CreateChatCompletionRequest) { r.Temperature = option.Ptr(option.Some(2.5)) }, "temperature"},
+		{"top_p", func(r *CreateChatCompletionRequest) { r.TopP = option.Ptr(option.Some(1.1)) }, "top_p"},
+		{"presence_penalty", func(r *CreateChatCompletionRequest) { r.PresencePenalty = option.Ptr(option.Some(-3.0)) }, "presence_penalty"},
+		{"n", func(r *CreateChatCompletionRequest) { r.N = option.Ptr(option.Some(0)) }, "n"},
+		{"top_logprobs range", func(r *CreateChatCompletionRequest) {
+			r.Logprobs = option.Ptr(option.Some(true))
+			r.TopLogprobs = option.Ptr(option.Some(21))
+		}, "top_logprobs"},
+		{"top_logprobs without logprobs", func(r *CreateChatCompletionRequest) { r.TopLogprobs = option.Ptr(option.Some(5)) }, "top_logprobs"},
+		{"max_tokens and max_completion_tokens", func(r *CreateChatCompletionRequest) {
+			r.MaxTokens = option.Ptr(option.Some(10))
+			r.MaxCompletionTokens = option.Ptr(option.Some(10))
+		}, "max_tokens"},
+		{"max_completion_tokens", func(r *CreateChatCompletionRequest) { r.MaxCompletionTokens = option.Ptr(option.Some(0)) }, "max_completion_tokens"},
+		{"functions and tools", func(r *CreateChatCompletionRequest) {
+			r.Tools = []ChatCompletionTool{weatherTool("a")}
+			r.Functions = []FunctionDefinition{{Name: "b"}}
+		}, "functions"},
+		{"function_call without functions", func(r *CreateChatCompletionRequest) { r.FunctionCall = FunctionCallAuto() }, "function_call"},
+		{"tool name pattern", func(r *CreateChatCompletionRequest) {
+			r.Tools = []ChatCompletionTool{weatherTool("ok"), weatherTool("get weather")}
+		}, "tools[1].function.name"},
+		{"tool name length", func(r *CreateChatCompletionRequest) {
+			r.Tools = []ChatCompletionTool{weatherTool(strings.Repeat("a", 65))}
+		}, "tools[0].function.name"},
+		{"duplicate tool", func(r *CreateChatCompletionRequest) {
+			r.Tools = []ChatCompletionTool{weatherTool("a"), weatherTool("a")}
+		}, "tools[1].function.name"},
+		{"tool_choice unknown function", func(r *CreateChatCompletionRequest) {
+			r.Tools = []ChatCompletionTool{weatherTool("a")}
+			r.ToolChoice = ToolChoiceFunction("b")
+		}, "tool_choice"},
+		{"tool_choice required without tools", func(r *CreateChatCompletionRequest) { r.ToolChoice = ToolChoiceRequired() }, "tool_choice"},
+		{"schema name", func(r *CreateChatCompletionRequest) {
+			r.ResponseFormat = NewJSONSchemaFormat("weather report", nil, true)
+		}, "response_format.json_schema.name"},
+		{"response format type", func(r *CreateChatCompletionRequest) {
+			r.ResponseFormat = &ResponseFormat{Type: "xml"}
+		}, "response_format"},
+		{"stop", func(r *CreateChatCompletionRequest) { r.Stop = StopSequences("a", "b", "c", "d", "e") }, "stop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validChatRequest()
+			tt.modify(&req)
+			checkFieldError(t, req.Validate(), tt.wantField)
+		})
+	}
+}
+
+func TestCreateChatCompletionRequest_ValidateMessageError(t *testing.T) {
+	req := validChatRequest()
+	req.Messages[0].Role = ""
+	if err := req.Validate(); !errors.Is(err, ErrInvalidMessage) {
+		t.Errorf("error = %v, want ErrInvalidMessage", err)
+	}
+}
+
+func TestCreateSpeechRequest_Validate(t *testing.T) {
+	valid := CreateSpeechRequest{Model: "playai-tts", Input: "Hello", Voice: "Fritz-PlayAI"}
+	tests := []struct {
+		name      string
+		modify    func(r *CreateSpeechRequest)
+		wantField string
+	}{
+		{"valid", func(r *CreateSpeechRequest) {
+			r.ResponseFormat = option.Ptr(option.Some("wav"))
+			r.SampleRate = option.Ptr(option.Some(48000))
+			r.Speed = option.Ptr(option.Some(1.5))
+		}, ""},
+		{"missing input", func(r *CreateSpeechRequest) { r.Input = "" }, "input"},
+		{"missing voice", func(r *CreateSpeechRequest) { r.Voice = "" }, "voice"},
+		{"format", func(r *CreateSpeechRequest) { r.ResponseFormat = option.Ptr(option.Some("aac")) }, "response_format"},
+		{"sample rate", func(r *CreateSpeechRequest) { r.SampleRate = option.Ptr(option.Some(11025)) }, "sample_rate"},
+		{"speed", func(r *CreateSpeechRequest) { r.Speed = option.Ptr(option.Some(0.1)) }, "speed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			checkFieldError(t, req.Validate(), tt.wantField)
+		})
+	}
+}
+
+func TestCreateTranscriptionRequest_Validate(t *testing.T) {
+	valid := CreateTranscriptionRequest{Model: "whisper-large-v3", File: "audio.mp3"}
+	tests := []struct {
+		name      string
+		modify    func(r *CreateTranscriptionRequest)
+		wantField string
+	}{
+		{"valid", func(r *CreateTranscriptionRequest) {
+			r.ResponseFormat = option.Ptr(option.Some("verbose_json"))
+			r.TimestampGranularities = []string{"word", "segment"}
+		}, ""},
+		{"url only", func(r *CreateTranscriptionRequest) {
+			r.File = nil
+			r.URL = option.Ptr(option.Some("https://example.com/a.mp3"))
+		}, ""},
+		{"no audio", func(r *CreateTranscriptionRequest) { r.File = nil }, "file"},
+		{"file and url", func(r *CreateTranscriptionRequest) { r.URL = option.Ptr(option.Some("https://example.com/a.mp3")) }, "url"},
+		{"temperature", func(r *CreateTranscriptionRequest) { r.Temperature = option.Ptr(option.Some(1.5)) }, "temperature"},
+		{"format", func(r *CreateTranscriptionRequest) { r.ResponseFormat = option.Ptr(option.Some("srt")) }, "response_format"},
+		{"granularity", func(r *CreateTranscriptionRequest) {
+			r.ResponseFormat = option.Ptr(option.Some("verbose_json"))
+			r.TimestampGranularities = []string{"sentence"}
+		}, "timestamp_granularities[0]"},
+		{"granularity without verbose_json", func(r *CreateTranscriptionRequest) {
+			r.TimestampGranularities = []string{"word"}
+		}, "timestamp_granularities[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			checkFieldError(t, req.Validate(), tt.wantField)
+		})
+	}
+}
+
+func TestCreateBatchRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       CreateBatchRequest
+		wantField string
+	}{
+		{"valid", CreateBatchRequest{InputFileID: "file_1", Endpoint: "/v1/chat/completions", CompletionWindow: "24h"}, ""},
+		{"valid days", CreateBatchRequest{InputFileID: "file_1", Endpoint: "/v1/audio/transcriptions", CompletionWindow: "7d"}, ""},
+		{"missing file", CreateBatchRequest{Endpoint: "/v1/chat/completions", CompletionWindow: "24h"}, "input_file_id"},
+		{"endpoint", CreateBatchRequest{InputFileID: "file_1", Endpoint: "/v1/completions", CompletionWindow: "24h"}, "endpoint"},
+		{"window format", CreateBatchRequest{InputFileID: "file_1", Endpoint: "/v1/chat/completions", CompletionWindow: "1w"}, "completion_window"},
+		{"window too short", CreateBatchRequest{InputFileID: "file_1", Endpoint: "/v1/chat/completions", CompletionWindow: "12h"}, "completion_window"},
+		{"window too long", CreateBatchRequest{InputFileID: "file_1", Endpoint: "/v1/chat/completions", CompletionWindow: "8d"}, "completion_window"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkFieldError(t, tt.req.Validate(), tt.wantField)
+		})
+	}
+}
+
+func checkFieldError(t *testing.T, err error, wantField string) {
+	t.Helper()
+	if wantField == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("error = %v (%T), want *FieldError", err, err)
+	}
+	if fieldErr.Field != wantField {
+		t.Errorf("field = %q, want %q (%v)", fieldErr.Field, wantField, err)
+	}
+}
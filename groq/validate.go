@@ -0,0 +1,53 @@
+package groq
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ZaguanLabs/groq-go/groq/models"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+// ValidateRequest runs the client-side checks of a request body that has a
+// Validate method, such as types.CreateChatCompletionRequest, and reports
+// the first problem as a *ValidationError. Chat completion requests are also
+// checked against the model's capabilities (see models.Capabilities). Other
+// bodies are accepted.
+func ValidateRequest(body interface{}) error {
+	v, ok := body.(interface{ Validate() error })
+	if !ok {
+		return nil
+	}
+	if err := v.Validate(); err != nil {
+		return newValidationError(err)
+	}
+	if req, ok := body.(*types.CreateChatCompletionRequest); ok {
+		if err := models.CheckRequest(req); err != nil {
+			return newValidationError(err)
+		}
+	}
+	return nil
+}
+
+func newValidationError(err error) *ValidationError {
+	verr := &ValidationError{GroqError: GroqError{Message: "invalid request: " + err.Error()}, Err: err}
+	var fieldErr *types.FieldError
+	if errors.As(err, &fieldErr) {
+		verr.Field = fieldErr.Field
+	}
+	return verr
+}
+
+// validateRequest fails on invalid requests when validation is enabled, and
+// otherwise only warns about features the model does not support
+func (c *Client) validateRequest(ctx context.Context, body interface{}) error {
+	if c.config.ValidateRequests {
+		return ValidateRequest(body)
+	}
+	if req, ok := body.(*types.CreateChatCompletionRequest); ok {
+		if err := models.CheckRequest(req); err != nil {
+			c.warn(ctx, "request uses unsupported model feature", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/groq-go/groq/option"
+	"github.com/ZaguanLabs/groq-go/groq/types"
+)
+
+func TestClient_RequestValidation(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	invalid := &types.CreateChatCompletionRequest{
+		Model:       "m",
+		Messages:    []types.ChatCompletionMessageParam{{Role: types.RoleUser, Content: "Hi"}},
+		Temperature: option.Ptr(option.Some(3.0)),
+	}
+
+	// Without the option the request is sent as is
+	c, _ := NewClient(WithAPIKey("test-key"), WithBaseURL(server.URL), WithMaxRetries(0))
+	if err := c.Post(context.Background(), "/openai/v1/chat/completions", invalid, nil); err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+
+	c, _ = NewClient(WithAPIKey("test-key"), WithBaseURL(server.URL), WithMaxRetries(0), WithRequestValidation())
+	err := c.Post(context.Background(), "/openai/v1/chat/completions", invalid, nil)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error = %v (%T), want *ValidationError", err, err)
+	}
+	if verr.Field != "temperature" {
+		t.Errorf("Field = %q, want temperature", verr.Field)
+	}
+	var fieldErr *types.FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Error("ValidationError does not unwrap to *types.FieldError")
+	}
+
+	if _, err := c.PostStream(context.Background(), "/openai/v1/audio/speech", &types.CreateSpeechRequest{Model: "m"}); !errors.As(err, &verr) || verr.Field != "input" {
+		t.Errorf("PostStream error = %v, want input ValidationError", err)
+	}
+	if err := c.PostForm(context.Background(), "/openai/v1/audio/transcriptions", &types.CreateTranscriptionRequest{Model: "m"}, nil); !errors.As(err, &verr) || verr.Field != "file" {
+		t.Errorf("PostForm error = %v, want file ValidationError", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("server calls = %d, want 1", calls)
+	}
+
+	// Bodies without a Validate method are not checked
+	if err := c.Post(context.Background(), "/test", map[string]string{"a": "b"}, nil); err != nil {
+		t.Errorf("Post error: %v", err)
+	}
+}
+
+func TestClient_ModelCapabilityCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	req := &types.CreateChatCompletionRequest{
+		Model:           string(types.ModelLlama33_70BVersatile),
+		Messages:        []types.ChatCompletionMessageParam{{Role: types.RoleUser, Content: "Hi"}},
+		ReasoningFormat: option.Ptr(option.Some("parsed")),
+	}
+
+	// Without validation the request is sent and a warning logged
+	var buf bytes.Buffer
+	c, _ := NewClient(WithAPIKey("test-key"), WithBaseURL(server.URL), WithLogHandler(slog.NewTextHandler(&buf, nil)))
+	if err := c.Post(context.Background(), "/openai/v1/chat/completions", req, nil); err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "level=WARN") || !strings.Contains(buf.String(), "reasoning_format") {
+		t.Errorf("log = %s, want reasoning_format warning", buf.String())
+	}
+
+	// With validation it fails fast
+	c, _ = NewClient(WithAPIKey("test-key"), WithBaseURL(server.URL), WithRequestValidation())
+	err := c.Post(context.Background(), "/openai/v1/chat/completions", req, nil)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "reasoning_format" {
+		t.Errorf("error = %v, want reasoning_format ValidationError", err)
+	}
+}